@@ -0,0 +1,92 @@
+package scraperapi
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// concurrencyLimitHeader is the response header with the concurrency limit of the plan.
+	concurrencyLimitHeader = "Concurrency-Limit"
+	// concurrencyRemainingHeader is the response header with the concurrency slots of the plan left.
+	concurrencyRemainingHeader = "Concurrency-Remaining"
+
+	// defaultAdaptiveConcurrency is the concurrency limit used in adaptive mode until a response reports the limit of the plan.
+	defaultAdaptiveConcurrency = 5
+)
+
+// WithAdaptiveConcurrency returns an Option which makes the client follow the concurrency limit of the plan, instead of a fixed
+// one. Every response reports the concurrency limit of the plan and the slots it has left, and the client resizes its limit
+// accordingly, leaving the slots used by other clients sharing the plan alone. WithMaxConcurrentRequests sets the limit used
+// until the first response (5 if unset).
+//
+// After a 429 Too Many Requests, the whole client stops sending new requests for the time set by the Retry-After header, or for an
+// exponential backoff starting at the retry wait time (see WithRetryWaitTime and WithRetryMaxWaitTime). See Client.Concurrency.
+func WithAdaptiveConcurrency() Option {
+	return newFuncDialOption(func(o *options) {
+		o.adaptiveConcurrency = true
+	})
+}
+
+// adaptiveTransport observes the concurrency headers and 429 Too Many Requests of every response, retries included, to adapt the
+// limiter of the client.
+type adaptiveTransport struct {
+	base    http.RoundTripper
+	limiter *limiter
+	retry   retryOptions
+	log     *slog.Logger
+}
+
+func (t *adaptiveTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	limit, hasLimit := headerInt(res.Header, concurrencyLimitHeader)
+	if hasLimit && limit > 0 {
+		remaining, hasRemaining := headerInt(res.Header, concurrencyRemainingHeader)
+		if !hasRemaining {
+			remaining = -1
+		}
+		t.limiter.resize(limit, remaining)
+	}
+
+	if res.StatusCode == http.StatusTooManyRequests {
+		retryAfter, _ := retryAfterDuration(res.Header)
+		wait, newLimit := t.limiter.throttle(retryAfter, t.retry.retryWaitTime, t.retry.retryMaxWaitTime, hasLimit)
+		t.log.InfoContext(req.Context(), "scraperapi: backoff wait after 429 Too Many Requests",
+			slog.Duration("wait", wait), slog.Int("limit", newLimit))
+	} else {
+		t.limiter.unthrottle()
+	}
+
+	return res, nil
+}
+
+// headerInt returns the integer value of a response header, if set.
+func headerInt(header http.Header, key string) (int, bool) {
+	value, err := strconv.Atoi(header.Get(key))
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+// retryAfterDuration returns the time to wait set by the Retry-After header, either in seconds or as an HTTP date, if set.
+func retryAfterDuration(header http.Header) (time.Duration, bool) {
+	raw := header.Get("Retry-After")
+	if raw == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.ParseFloat(raw, 64); err == nil && secs >= 0 {
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	if at, err := http.ParseTime(raw); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
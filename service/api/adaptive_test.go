@@ -0,0 +1,102 @@
+package scraperapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+func TestAdaptiveConcurrencyFollowsConcurrencyHeaders(t *testing.T) {
+	var remaining atomic.Int32
+	remaining.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Concurrency-Limit", "3")
+		w.Header().Set("Concurrency-Remaining", strconv.Itoa(int(remaining.Load())))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithMaxConcurrentRequests(10),
+		scraperapi.WithAdaptiveConcurrency(),
+	)
+	if limit := client.Concurrency().Limit; limit != 10 {
+		t.Fatalf("expected the configured limit before the first response, got %d", limit)
+	}
+
+	// another client of the plan holds a slot: 1 slot held by this request plus 1 left
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limit := client.Concurrency().Limit; limit != 2 {
+		t.Fatalf("expected the limit to leave the slot used elsewhere alone, got %d", limit)
+	}
+
+	// the other client is done: the limit grows back up to the limit of the plan
+	remaining.Store(2)
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limit := client.Concurrency().Limit; limit != 3 {
+		t.Fatalf("expected the limit of the plan, got %d", limit)
+	}
+}
+
+func TestAdaptiveConcurrencyPausesClientAfterTooManyRequests(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithMaxConcurrentRequests(4),
+		scraperapi.WithRetryWaitTime(200*time.Millisecond),
+		scraperapi.WithAdaptiveConcurrency(),
+	)
+
+	res, err := client.Get(context.Background(), "https://example.com", nil)
+	if err != nil || res.StatusCode() != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429 response, got %v (%v)", res, err)
+	}
+
+	stats := client.Concurrency()
+	if stats.PausedUntil.IsZero() || stats.Limit != 2 {
+		t.Fatalf("expected the client to be paused and its limit halved, got %+v", stats)
+	}
+
+	start := time.Now()
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("expected the next request to wait for the backoff, it took %s", elapsed)
+	}
+}
+
+func TestConcurrencyHeadersAreIgnoredByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Concurrency-Limit", "1")
+		w.Header().Set("Concurrency-Remaining", "0")
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithMaxConcurrentRequests(5))
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limit := client.Concurrency().Limit; limit != 5 {
+		t.Fatalf("expected the fixed limit to be kept, got %d", limit)
+	}
+}
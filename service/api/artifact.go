@@ -0,0 +1,255 @@
+package scraperapi
+
+import (
+	"bytes"
+	"image"
+	_ "image/jpeg" // register the JPEG decoder for Response.Screenshot
+	_ "image/png"  // register the PNG decoder for Response.Screenshot
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	contentTypePNG  = "image/png"
+	contentTypeJPEG = "image/jpeg"
+	contentTypePDF  = "application/pdf"
+	contentTypeJSON = "application/json"
+
+	artifactDirPerm  = 0o755 // directories created by Response.SaveArtifact
+	artifactFilePerm = 0o600 // artifact files - owner read/write only
+)
+
+// artifactExtensions maps the content types of the binary artifacts the ZenRows Fetch API can return to their file extension.
+var artifactExtensions = map[string]string{
+	contentTypePNG:  ".png",
+	contentTypeJPEG: ".jpg",
+	contentTypePDF:  ".pdf",
+}
+
+// screenshotContentTypes maps every ScreenshotFormat to the content type the API returns it with.
+var screenshotContentTypes = map[ScreenshotFormat]string{
+	ScreenshotFormatPNG:  contentTypePNG,
+	ScreenshotFormatJPEG: contentTypeJPEG,
+}
+
+// mediaType returns the lower-cased media type of the response, without parameters such as the charset.
+func (r *Response) mediaType() string {
+	contentType := r.Header().Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
+
+// unexpectedContent builds the UnexpectedContentError returned by the artifact helpers, wrapping the response error, if any.
+func (r *Response) unexpectedContent(expected string) UnexpectedContentError {
+	return UnexpectedContentError{Expected: expected, ContentType: r.Header().Get("Content-Type"), Err: r.Error()}
+}
+
+// streamedContent returns the UnexpectedContentError, wrapping a StreamedBodyError, returned by the helpers that decode the body
+// when the response is streamed (see Client.ScrapeStream), or nil if the body is buffered.
+func (r *Response) streamedContent(expected string) error {
+	if r.stream == nil {
+		return nil
+	}
+	return UnexpectedContentError{Expected: expected, ContentType: r.Header().Get("Content-Type"), Err: StreamedBodyError{}}
+}
+
+// Screenshot method returns the screenshot carried by the response, decoded as an image.Image, along with its raw bytes. It
+// handles both the raw image body returned for RequestParameters.Screenshot and the base64 screenshot embedded in a json_response
+// envelope. It returns an UnexpectedContentError if the response does not carry a screenshot, e.g. a problem+json body, or if it is
+// streamed (see Client.ScrapeStream).
+func (r *Response) Screenshot() (image.Image, []byte, error) {
+	data, _, err := r.screenshotBytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, UnexpectedContentError{Expected: "screenshot", ContentType: r.Header().Get("Content-Type"), Err: err}
+	}
+
+	return img, data, nil
+}
+
+// screenshotBytes returns the raw screenshot bytes and their content type.
+func (r *Response) screenshotBytes() (data []byte, contentType string, err error) {
+	if r.IsError() {
+		return nil, "", r.unexpectedContent("screenshot")
+	}
+	if err := r.streamedContent("screenshot"); err != nil {
+		return nil, "", err
+	}
+
+	mediaType := r.mediaType()
+	switch {
+	case strings.HasPrefix(mediaType, "image/"):
+		return r.Body(), mediaType, nil
+	case mediaType == contentTypeJSON || (r.params != nil && r.params.JSONResponse):
+		envelope, jsonErr := r.JSON()
+		if jsonErr != nil {
+			return nil, "", jsonErr
+		}
+		if envelope.Screenshot == nil || envelope.Screenshot.Data == "" {
+			return nil, "", r.unexpectedContent("screenshot")
+		}
+		data, err = envelope.Screenshot.Bytes()
+		if err != nil {
+			return nil, "", UnexpectedContentError{Expected: "base64 screenshot", ContentType: mediaType, Err: err}
+		}
+		contentType = envelope.Screenshot.Type
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		return data, contentType, nil
+	case r.params != nil && r.params.Screenshot:
+		// the API returned the image without a usable content type, so trust what was requested
+		return r.Body(), r.requestedScreenshotContentType(), nil
+	default:
+		return nil, "", r.unexpectedContent("screenshot")
+	}
+}
+
+// requestedScreenshotContentType returns the content type matching the requested ScreenshotFormat, which defaults to PNG.
+func (r *Response) requestedScreenshotContentType() string {
+	if r.params != nil {
+		if contentType, ok := screenshotContentTypes[r.params.ScreenshotFormat]; ok {
+			return contentType
+		}
+	}
+	return contentTypePNG
+}
+
+// PDF method returns the PDF document carried by a response requested with ResponseTypePDF. It returns an UnexpectedContentError
+// if the response does not carry a PDF, e.g. a problem+json body, or if it is streamed (see Client.ScrapeStream); save streamed
+// PDFs with Response.SaveArtifact, or read them from Response.BodyReader.
+func (r *Response) PDF() ([]byte, error) {
+	if r.IsError() {
+		return nil, r.unexpectedContent("pdf")
+	}
+	if err := r.streamedContent("pdf"); err != nil {
+		return nil, err
+	}
+
+	body := r.Body()
+	if r.mediaType() == contentTypePDF || bytes.HasPrefix(body, []byte("%PDF-")) {
+		return body, nil
+	}
+
+	return nil, r.unexpectedContent("pdf")
+}
+
+// SaveArtifact method writes the binary artifact carried by the response (a PDF or a screenshot) to path, and returns the path
+// written. If path has no extension, one is picked from the response content type and the requested ResponseType and
+// ScreenshotFormat. It returns an UnexpectedContentError if the response does not carry a binary artifact.
+//
+// For responses returned by Client.ScrapeStream, it copies the body stream to the file as it is read, without buffering it; the
+// response must still be closed afterward.
+func (r *Response) SaveArtifact(path string) (string, error) {
+	if r.stream != nil {
+		return r.saveStreamedArtifact(path)
+	}
+
+	data, contentType, err := r.artifact()
+	if err != nil {
+		return "", err
+	}
+
+	path, err = artifactPath(path, contentType)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, artifactFilePerm); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// saveStreamedArtifact writes the binary artifact carried by a streamed response to path, copying it from the body stream. The
+// content type is told by the response header and the request parameters, since the body cannot be sniffed before it is read.
+func (r *Response) saveStreamedArtifact(path string) (string, error) {
+	contentType, err := r.streamedArtifactContentType()
+	if err != nil {
+		return "", err
+	}
+
+	path, err = artifactPath(path, contentType)
+	if err != nil {
+		return "", err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, artifactFilePerm)
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(file, r.stream); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// streamedArtifactContentType returns the content type of the binary artifact carried by a streamed response. Screenshots
+// embedded in a json_response envelope must be decoded, so they cannot be saved from a stream.
+func (r *Response) streamedArtifactContentType() (string, error) {
+	if r.IsError() {
+		return "", r.unexpectedContent("binary artifact")
+	}
+
+	mediaType := r.mediaType()
+	switch {
+	case mediaType == contentTypePDF || (r.params != nil && r.params.ResponseType == ResponseTypePDF):
+		return contentTypePDF, nil
+	case strings.HasPrefix(mediaType, "image/"):
+		if _, known := artifactExtensions[mediaType]; known {
+			return mediaType, nil
+		}
+		return r.requestedScreenshotContentType(), nil
+	case mediaType == contentTypeJSON || (r.params != nil && r.params.JSONResponse):
+		return "", r.streamedContent("binary artifact")
+	case r.params != nil && r.params.Screenshot:
+		return r.requestedScreenshotContentType(), nil
+	default:
+		return "", r.unexpectedContent("binary artifact")
+	}
+}
+
+// artifactPath returns path with the extension of contentType appended, if it has none, after creating its parent directory.
+func artifactPath(path, contentType string) (string, error) {
+	if filepath.Ext(path) == "" {
+		path += artifactExtensions[contentType]
+	}
+	if err := os.MkdirAll(filepath.Dir(path), artifactDirPerm); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// artifact returns the binary artifact carried by the response and its content type.
+func (r *Response) artifact() (data []byte, contentType string, err error) {
+	wantsPDF := r.params != nil && r.params.ResponseType == ResponseTypePDF
+	if wantsPDF || r.mediaType() == contentTypePDF {
+		data, err = r.PDF()
+		return data, contentTypePDF, err
+	}
+
+	data, contentType, err = r.screenshotBytes()
+	if err != nil {
+		return nil, "", err
+	}
+	if _, known := artifactExtensions[contentType]; !known {
+		contentType = r.requestedScreenshotContentType()
+	}
+	return data, contentType, nil
+}
@@ -0,0 +1,256 @@
+package scraperapi_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
+)
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 2, 3))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func doGetWithParams(t *testing.T, params *scraperapi.RequestParameters, handler http.HandlerFunc) *scraperapi.Response {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("test-key"))
+	res, err := client.Get(context.Background(), "https://example.com", params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return res
+}
+
+func TestResponseScreenshotFromRawBody(t *testing.T) {
+	pngBytes := testPNG(t)
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(pngBytes)
+	})
+
+	img, raw, err := res.Screenshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if img.Bounds().Dx() != 2 || img.Bounds().Dy() != 3 {
+		t.Fatalf("unexpected image bounds: %v", img.Bounds())
+	}
+	if !bytes.Equal(raw, pngBytes) {
+		t.Fatal("expected the raw bytes to be returned as-is")
+	}
+}
+
+func TestResponseScreenshotFromJSONEnvelope(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(testPNG(t))
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"html":"<html></html>","screenshot":{"data":"` + encoded + `","type":"image/png"}}`))
+	})
+
+	img, _, err := res.Screenshot()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if img.Bounds().Dx() != 2 {
+		t.Fatalf("unexpected image bounds: %v", img.Bounds())
+	}
+}
+
+func TestResponseScreenshotRejectsProblemBody(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"title":"Could not get content","status":422,"code":"RESP001"}`))
+	})
+
+	_, _, err := res.Screenshot()
+	var unexpected scraperapi.UnexpectedContentError
+	if !errors.As(err, &unexpected) {
+		t.Fatalf("expected UnexpectedContentError, got %v", err)
+	}
+	var prob *problem.Problem
+	if !errors.As(err, &prob) {
+		t.Fatalf("expected the error to wrap the problem, got %v", err)
+	}
+}
+
+func TestResponseScreenshotRejectsHTMLBody(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+
+	var unexpected scraperapi.UnexpectedContentError
+	if _, _, err := res.Screenshot(); !errors.As(err, &unexpected) {
+		t.Fatalf("expected UnexpectedContentError, got %v", err)
+	}
+}
+
+func TestResponseSaveArtifactPicksExtensionFromContentType(t *testing.T) {
+	pngBytes := testPNG(t)
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(pngBytes)
+	})
+
+	path, err := res.SaveArtifact(filepath.Join(t.TempDir(), "shots", "home"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Ext(path) != ".png" {
+		t.Fatalf("expected a .png extension, got %q", path)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(written, pngBytes) {
+		t.Fatalf("expected the screenshot to be written to %s (%v)", path, err)
+	}
+}
+
+func TestResponseSaveArtifactUsesRequestedPDFResponseType(t *testing.T) {
+	params := &scraperapi.RequestParameters{ResponseType: scraperapi.ResponseTypePDF}
+	res := doGetWithParams(t, params, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write([]byte("%PDF-1.7\n..."))
+	})
+
+	path, err := res.SaveArtifact(filepath.Join(t.TempDir(), "page"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Ext(path) != ".pdf" {
+		t.Fatalf("expected a .pdf extension, got %q", path)
+	}
+}
+
+func TestResponseSaveArtifactKeepsExplicitExtension(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write([]byte("%PDF-1.7\n..."))
+	})
+
+	want := filepath.Join(t.TempDir(), "page.bin")
+	if path, err := res.SaveArtifact(want); err != nil || path != want {
+		t.Fatalf("expected %s, got %s (%v)", want, path, err)
+	}
+}
+
+func TestResponsePDFRejectsNonPDFBody(t *testing.T) {
+	params := &scraperapi.RequestParameters{ResponseType: scraperapi.ResponseTypePDF}
+	res := doGetWithParams(t, params, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+
+	var unexpected scraperapi.UnexpectedContentError
+	if _, err := res.PDF(); !errors.As(err, &unexpected) {
+		t.Fatalf("expected UnexpectedContentError, got %v", err)
+	}
+	if _, err := res.SaveArtifact(filepath.Join(t.TempDir(), "page")); !errors.As(err, &unexpected) {
+		t.Fatalf("expected UnexpectedContentError from SaveArtifact, got %v", err)
+	}
+}
+
+func doGetStreamWithParams(t *testing.T, params *scraperapi.RequestParameters, handler http.HandlerFunc) *scraperapi.Response {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("test-key"))
+	res, err := client.GetStream(context.Background(), "https://example.com", params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = res.Close() })
+	return res
+}
+
+func TestResponseSaveArtifactCopiesStreamedBody(t *testing.T) {
+	pdf := []byte("%PDF-1.7\nstreamed")
+	params := &scraperapi.RequestParameters{ResponseType: scraperapi.ResponseTypePDF}
+	res := doGetStreamWithParams(t, params, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(pdf)
+	})
+
+	path, err := res.SaveArtifact(filepath.Join(t.TempDir(), "page"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filepath.Ext(path) != ".pdf" {
+		t.Fatalf("expected a .pdf extension, got %q", path)
+	}
+	written, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(written, pdf) {
+		t.Fatalf("expected the streamed pdf to be written to %s, got %q (%v)", path, written, err)
+	}
+}
+
+func TestResponseSaveArtifactRemovesFileWhenStreamFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		// flush before writing everything, so the body is chunked and only fails the size cap while it is copied
+		_, _ = w.Write([]byte("%PDF-1.7"))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(strings.Repeat("x", 16)))
+	}))
+	t.Cleanup(server.Close)
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("test-key"),
+		scraperapi.WithMaxStreamBodySize(10))
+	res, err := client.GetStream(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Close()
+
+	dir := t.TempDir()
+	var tooLarge scraperapi.BodyTooLargeError
+	if _, err := res.SaveArtifact(filepath.Join(dir, "page")); !errors.As(err, &tooLarge) {
+		t.Fatalf("expected BodyTooLargeError, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("expected the partial artifact to be removed, found %v", entries)
+	}
+}
+
+func TestResponseDecodersRejectStreamedBody(t *testing.T) {
+	res := doGetStreamWithParams(t, nil, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write([]byte("%PDF-1.7\n..."))
+	})
+
+	decoders := map[string]func() error{
+		"PDF":        func() error { _, err := res.PDF(); return err },
+		"Screenshot": func() error { _, _, err := res.Screenshot(); return err },
+		"JSON":       func() error { _, err := res.JSON(); return err },
+		"Outputs":    func() error { _, err := res.Outputs(); return err },
+	}
+	for name, decode := range decoders {
+		var streamed scraperapi.StreamedBodyError
+		if err := decode(); !errors.As(err, &streamed) {
+			t.Errorf("%s: expected StreamedBodyError, got %v", name, err)
+		}
+	}
+}
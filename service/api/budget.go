@@ -0,0 +1,146 @@
+package scraperapi
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Budget is a credit ceiling. Before a request is sent, its worst-case cost is reserved from the budget, and the request is
+// rejected with a BudgetExceededError if that would exceed the ceiling; once the response arrives, the reservation is replaced by
+// the credits actually charged. Every attempt of a retried request is reserved and charged on its own. It is safe for concurrent
+// use.
+type Budget struct {
+	mu       sync.Mutex
+	limit    float64
+	spent    float64
+	reserved float64
+}
+
+// NewBudget creates and returns a new Budget of the given number of credits.
+func NewBudget(credits float64) *Budget {
+	return &Budget{limit: credits}
+}
+
+// Limit returns the credit ceiling of the budget.
+func (b *Budget) Limit() float64 {
+	return b.limit
+}
+
+// Spent returns the credits charged so far.
+func (b *Budget) Spent() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.spent
+}
+
+// Remaining returns the credits left, not counting the worst-case cost reserved for the requests in flight.
+func (b *Budget) Remaining() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.limit - b.spent - b.reserved
+}
+
+// reserve reserves credits for a request, unless that would exceed the budget.
+func (b *Budget) reserve(credits float64, scope string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.spent+b.reserved+credits > b.limit {
+		return BudgetExceededError{Scope: scope, Limit: b.limit, Spent: b.spent, Reserved: b.reserved, Required: credits}
+	}
+	b.reserved += credits
+	return nil
+}
+
+// settle replaces the credits reserved for a request with the credits it was charged.
+func (b *Budget) settle(reserved, charged float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.reserved -= reserved
+	b.spent += charged
+}
+
+type budgetContextKey struct{}
+
+// ContextWithBudget returns a copy of ctx whose requests are charged to the given budget, on top of the budget of the client, if
+// any (see WithBudget). Share the budget between contexts to cap the spend of a whole job.
+//
+//	budget := scraperapi.NewBudget(500)
+//	ctx := scraperapi.ContextWithBudget(context.Background(), budget)
+func ContextWithBudget(ctx context.Context, budget *Budget) context.Context {
+	return context.WithValue(ctx, budgetContextKey{}, budget)
+}
+
+// budgetFromContext returns the budget set on ctx with ContextWithBudget, if any.
+func budgetFromContext(ctx context.Context) *Budget {
+	budget, _ := ctx.Value(budgetContextKey{}).(*Budget)
+	return budget
+}
+
+// WithBudget returns an Option which caps the credits the client can spend. Requests whose worst-case cost (see EstimateCost) would
+// exceed what is left are rejected with a BudgetExceededError before being sent. See ContextWithBudget to cap the spend of a
+// context too.
+func WithBudget(credits float64) Option {
+	return newFuncDialOption(func(o *options) {
+		o.budget = NewBudget(credits)
+	})
+}
+
+// Budget returns the budget of the client set with WithBudget, or nil.
+func (c *Client) Budget() *Budget {
+	return c.cfg.budget
+}
+
+// reserveBudget reserves the worst-case cost of a request sent with params from the budgets of the client and ctx. It returns the
+// function that settles the reservations with the credits charged for the response, or releases them if there is no response.
+func (c *Client) reserveBudget(ctx context.Context, params *RequestParameters) (settle func(*Response), err error) {
+	type reservation struct {
+		budget *Budget
+		scope  string
+	}
+
+	var reservations []reservation
+	if c.cfg.budget != nil {
+		reservations = append(reservations, reservation{budget: c.cfg.budget, scope: "client"})
+	}
+	if budget := budgetFromContext(ctx); budget != nil {
+		reservations = append(reservations, reservation{budget: budget, scope: "context"})
+	}
+
+	worstCase := float64(EstimateCost(params, 1).Max)
+	for i, r := range reservations {
+		if err := r.budget.reserve(worstCase, r.scope); err != nil {
+			for _, reserved := range reservations[:i] {
+				reserved.budget.settle(worstCase, 0)
+			}
+			return nil, err
+		}
+	}
+
+	return func(response *Response) {
+		charged := 0.0
+		if response != nil {
+			charged = response.chargedCredits(worstCase)
+		}
+		for _, r := range reservations {
+			r.budget.settle(worstCase, charged)
+		}
+	}, nil
+}
+
+// chargedCredits returns the credits charged for the response, as reported by the response, or worstCase if it does not report
+// them and it is charged: successful responses, and responses whose status code is allowed by AllowedStatusCodes.
+func (r *Response) chargedCredits(worstCase float64) float64 {
+	if cost := r.Cost(); cost.Reported {
+		return cost.Credits
+	}
+
+	if r.IsSuccess() || (r.params != nil && slices.Contains(r.params.AllowedStatusCodes, r.StatusCode())) {
+		return worstCase
+	}
+	return 0
+}
@@ -0,0 +1,131 @@
+package scraperapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+func TestWithBudgetRejectsRequestsThatMayExceedIt(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-Request-Cost", "5")
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithBudget(30))
+
+	// ModeAuto may cost up to 25 credits, but it is reconciled with the 5 actually charged
+	auto := &scraperapi.RequestParameters{Mode: scraperapi.ModeAuto}
+	if _, err := client.Get(context.Background(), "https://example.com", auto); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spent, remaining := client.Budget().Spent(), client.Budget().Remaining(); spent != 5 || remaining != 25 {
+		t.Fatalf("expected the actual cost to be charged, got spent=%g remaining=%g", spent, remaining)
+	}
+
+	if _, err := client.Get(context.Background(), "https://example.com", auto); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 20 credits are left, less than the worst case of ModeAuto
+	_, err := client.Get(context.Background(), "https://example.com", auto)
+	var exceeded scraperapi.BudgetExceededError
+	if !errors.As(err, &exceeded) || exceeded.Scope != "client" || exceeded.Required != scraperapi.AutoMaxCredits {
+		t.Fatalf("expected BudgetExceededError, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected the rejected request not to be sent, got %d calls", calls.Load())
+	}
+
+	// a JSRender request still fits
+	if _, err := client.Get(context.Background(), "https://example.com", &scraperapi.RequestParameters{JSRender: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestContextWithBudgetCapsRequestsOfTheContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"))
+	budget := scraperapi.NewBudget(2)
+	ctx := scraperapi.ContextWithBudget(context.Background(), budget)
+
+	// without the cost header, successful responses are charged their worst case
+	for range 2 {
+		if _, err := client.Get(ctx, "https://example.com", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	var exceeded scraperapi.BudgetExceededError
+	if _, err := client.Get(ctx, "https://example.com", nil); !errors.As(err, &exceeded) || exceeded.Scope != "context" {
+		t.Fatalf("expected BudgetExceededError, got %v", err)
+	}
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("expected requests outside the context not to be capped, got %v", err)
+	}
+}
+
+func TestBudgetDoesNotChargeUnsuccessfulRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithBudget(10))
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spent := client.Budget().Spent(); spent != 0 {
+		t.Fatalf("expected the failed request not to be charged, got %g", spent)
+	}
+
+	params := &scraperapi.RequestParameters{AllowedStatusCodes: []int{http.StatusNotFound}}
+	if _, err := client.Get(context.Background(), "https://example.com", params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spent := client.Budget().Spent(); spent != scraperapi.BaseCredits {
+		t.Fatalf("expected the allowed status code to be charged, got %g", spent)
+	}
+}
+
+func TestBudgetChargesEveryRetriedAttempt(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// the target blocked the request, but it was still charged
+		w.Header().Set("X-Request-Cost", "5")
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"code":"RESP001","status":422}`))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithBudget(12),
+		scraperapi.WithMaxRetryCount(5), scraperapi.WithRetryWaitTime(time.Millisecond))
+
+	// every attempt may cost up to 5 credits: two fit in the budget, and the third retry is rejected before being sent
+	_, err := client.Get(context.Background(), "https://example.com", &scraperapi.RequestParameters{JSRender: true})
+	var exceeded scraperapi.BudgetExceededError
+	if !errors.As(err, &exceeded) || exceeded.Spent != 10 {
+		t.Fatalf("expected BudgetExceededError after two charged attempts, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected two attempts to be sent, got %d", calls.Load())
+	}
+	if spent, remaining := client.Budget().Spent(), client.Budget().Remaining(); spent != 10 || remaining != 2 {
+		t.Fatalf("expected both attempts to be charged, got spent=%g remaining=%g", spent, remaining)
+	}
+	if total := client.Spend().Snapshot().Total; total.Requests != 2 || total.Credits != 10 {
+		t.Fatalf("expected both attempts in the spend meter, got %+v", total)
+	}
+}
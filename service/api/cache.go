@@ -0,0 +1,314 @@
+package scraperapi
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// defaultCacheTTL is how long cached responses are served, unless set with WithCacheTTL or ContextWithCacheTTL.
+const defaultCacheTTL = 24 * time.Hour
+
+// WithCache returns an Option which configures a cache of responses: a request sent again with the same method, target URL,
+// parameters and body is answered from the cache, without being charged, until the cached response expires (see WithCacheTTL).
+// Error responses are not cached (see WithCacheErrorResponses), and neither are streamed responses. Defaults to nil (no cache).
+//
+// See NewMemoryCache and NewDiskCache for the caches shipped with the SDK.
+func WithCache(cache Cache) Option {
+	return newFuncDialOption(func(o *options) {
+		o.cache = cache
+	})
+}
+
+// WithCacheTTL returns an Option which configures how long cached responses are served. It can be overridden per request with
+// ContextWithCacheTTL. Defaults to 24 hours.
+func WithCacheTTL(ttl time.Duration) Option {
+	return newFuncDialOption(func(o *options) {
+		o.cacheTTL = ttl
+	})
+}
+
+// WithCacheErrorResponses returns an Option which makes the cache of the client store error responses too (see Response.IsError),
+// e.g. to avoid paying again for targets known to fail. Defaults to false.
+func WithCacheErrorResponses() Option {
+	return newFuncDialOption(func(o *options) {
+		o.cacheErrorResponses = true
+	})
+}
+
+type cacheTTLContextKey struct{}
+
+// ContextWithCacheTTL returns a copy of ctx whose responses are cached for the given duration instead of the TTL of the client
+// (see WithCacheTTL). It only applies to responses stored from now on.
+func ContextWithCacheTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, cacheTTLContextKey{}, ttl)
+}
+
+type cacheBypassContextKey struct{}
+
+// ContextWithoutCache returns a copy of ctx whose requests bypass the cache of the client: they are always sent, and their
+// responses are not stored.
+func ContextWithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassContextKey{}, true)
+}
+
+// CacheEntry is a response stored in a Cache.
+type CacheEntry struct {
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	// StoredAt is when the response was received and stored.
+	StoredAt time.Time `json:"stored_at"`
+	// ExpiresAt is when the response stops being served from the cache.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Cache stores responses of the ZenRows Fetch API by request key. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the entry stored for the key, if any. Expired entries are ignored, and deleted, by the client.
+	Get(key string) (CacheEntry, bool)
+	// Set stores the entry for the key.
+	Set(key string, entry CacheEntry)
+	// Delete deletes the entry stored for the key, if any.
+	Delete(key string)
+}
+
+// MemoryCache is an in-memory Cache that holds up to a maximum number of entries, evicting the least recently used ones. It is
+// safe for concurrent use.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // of *memoryCacheItem, the most recently used first
+	items      map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewMemoryCache creates and returns a new, empty MemoryCache holding up to maxEntries entries, or an unlimited number of entries if
+// maxEntries is 0 or less.
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{maxEntries: maxEntries, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*memoryCacheItem).entry, true
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		element.Value.(*memoryCacheItem).entry = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&memoryCacheItem{key: key, entry: entry})
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*memoryCacheItem).key)
+	}
+}
+
+// Delete implements Cache.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.Remove(element)
+		delete(c.items, key)
+	}
+}
+
+// Len returns the number of entries in the cache, expired ones included.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// DiskCache is a Cache that stores every entry as a JSON file in a directory, so cached responses survive between runs. Entries
+// that cannot be read or written are treated as missing. It is safe for concurrent use.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates and returns a new DiskCache storing its entries in dir, which is created if it does not exist.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get implements Cache.
+func (c *DiskCache) Get(key string) (CacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+
+	var entry CacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Set implements Cache. The entry is written to a temporary file first, so concurrent readers never see a partial entry.
+func (c *DiskCache) Set(key string, entry CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	f, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), c.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+}
+
+// Delete implements Cache.
+func (c *DiskCache) Delete(key string) {
+	_ = os.Remove(c.path(key))
+}
+
+// path returns the path of the file storing the entry of the key. Keys are hashed, so any key maps to a valid file name.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// cacheKey returns the key of a request in the cache: a hash of its method, target URL, canonicalized parameters, custom headers
+// and body. It returns false for requests whose body cannot be read without consuming it, which are not cached.
+func cacheKey(req *Request) (string, bool) {
+	var body []byte
+	switch b := req.Body.(type) {
+	case nil:
+	case []byte:
+		body = b
+	case string:
+		body = []byte(b)
+	case io.Reader:
+		return "", false
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return "", false
+		}
+		body = data
+	}
+
+	var head strings.Builder
+	head.WriteString(strings.ToUpper(req.Method) + "\n" + req.TargetURL + "\n")
+	if req.Params != nil {
+		// url.Values.Encode and http.Header.Write sort by key, so the same parameters always give the same key
+		head.WriteString(req.Params.ToURLValues().Encode() + "\n")
+		_ = req.Params.CustomHeaders.Write(&head)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(head.String()))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), true
+}
+
+// cacheFor returns the cache of the client and the key of the request in it, or false if the request is not cached: the client has
+// no cache, the request is streamed or bypasses the cache (see ContextWithoutCache), or its body cannot be hashed.
+func (c *Client) cacheFor(ctx context.Context, req *Request) (Cache, string, bool) {
+	if c.cfg.cache == nil || req.Stream {
+		return nil, "", false
+	}
+	if bypass, _ := ctx.Value(cacheBypassContextKey{}).(bool); bypass {
+		return nil, "", false
+	}
+
+	key, ok := cacheKey(req)
+	return c.cfg.cache, key, ok
+}
+
+// cachedResponse returns the response stored in the cache for the key, if it has not expired.
+func cachedResponse(cache Cache, key string, req *Request) (*Response, bool) {
+	entry, ok := cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(entry.ExpiresAt) {
+		cache.Delete(key)
+		return nil, false
+	}
+
+	raw := &http.Response{
+		Status:     entry.Status,
+		StatusCode: entry.StatusCode,
+		Header:     entry.Header,
+		Body:       io.NopCloser(bytes.NewReader(entry.Body)),
+	}
+	res := (&resty.Response{RawResponse: raw}).SetBody(entry.Body)
+	return &Response{res: res, targetURL: req.TargetURL, params: req.Params, cachedAt: entry.StoredAt}, true
+}
+
+// storeResponse stores a response in the cache for the key, unless it is an error response and the client does not cache those.
+func (c *Client) storeResponse(ctx context.Context, cache Cache, key string, response *Response) {
+	if response.IsError() && !c.cfg.cacheErrorResponses {
+		return
+	}
+
+	ttl := c.cfg.cacheTTL
+	if override, ok := ctx.Value(cacheTTLContextKey{}).(time.Duration); ok {
+		ttl = override
+	}
+	if ttl <= 0 {
+		ttl = defaultCacheTTL
+	}
+
+	now := time.Now()
+	cache.Set(key, CacheEntry{
+		StatusCode: response.StatusCode(),
+		Status:     response.Status(),
+		Header:     response.Header().Clone(),
+		Body:       bytes.Clone(response.Body()),
+		StoredAt:   now,
+		ExpiresAt:  now.Add(ttl),
+	})
+}
@@ -0,0 +1,171 @@
+package scraperapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+func newCacheTestServer(t *testing.T, status int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("X-Request-Cost", "1")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("body of " + r.URL.Query().Get("url")))
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func TestWithCacheServesRepeatedRequestsFromTheCache(t *testing.T) {
+	server, hits := newCacheTestServer(t, http.StatusOK)
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithCache(scraperapi.NewMemoryCache(10)),
+	)
+
+	ctx := context.Background()
+	first, err := client.Get(ctx, "https://example.com", &scraperapi.RequestParameters{JSRender: true, WaitMilliseconds: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if first.FromCache() {
+		t.Fatal("expected the first response to come from the API")
+	}
+
+	// the same parameters, set in another order, give the same cache key
+	second, err := client.Get(ctx, "https://example.com", &scraperapi.RequestParameters{WaitMilliseconds: 100, JSRender: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !second.FromCache() || second.String() != "body of https://example.com" || second.StatusCode() != http.StatusOK {
+		t.Fatalf("expected the cached response, got %d %q (from cache: %v)", second.StatusCode(), second.String(), second.FromCache())
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected 1 request to the API, got %d", hits.Load())
+	}
+	if spent := client.Spend().Snapshot().Total.Credits; spent != 1 {
+		t.Errorf("expected cached responses not to be charged, got %g credits", spent)
+	}
+
+	// different parameters or bodies are different requests
+	if _, err = client.Get(ctx, "https://example.com", &scraperapi.RequestParameters{JSRender: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = client.Post(ctx, "https://example.com", nil, map[string]string{"a": "b"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hits.Load() != 3 {
+		t.Fatalf("expected 3 requests to the API, got %d", hits.Load())
+	}
+}
+
+func TestWithCacheBypassAndTTL(t *testing.T) {
+	server, hits := newCacheTestServer(t, http.StatusOK)
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithCache(scraperapi.NewMemoryCache(0)),
+	)
+
+	ctx := context.Background()
+	for range 2 {
+		if _, err := client.Get(scraperapi.ContextWithoutCache(ctx), "https://example.com", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if hits.Load() != 2 {
+		t.Fatalf("expected requests bypassing the cache to be sent, got %d requests", hits.Load())
+	}
+
+	for range 2 {
+		if _, err := client.Get(scraperapi.ContextWithCacheTTL(ctx, time.Nanosecond), "https://example.com", nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if hits.Load() != 4 {
+		t.Fatalf("expected expired responses to be sent again, got %d requests", hits.Load())
+	}
+}
+
+func TestWithCacheSkipsErrorResponsesUnlessConfigured(t *testing.T) {
+	for _, cacheErrors := range []bool{false, true} {
+		server, hits := newCacheTestServer(t, http.StatusUnprocessableEntity)
+		opts := []scraperapi.Option{
+			scraperapi.WithBaseURL(server.URL),
+			scraperapi.WithAPIKey("k"),
+			scraperapi.WithCache(scraperapi.NewMemoryCache(10)),
+		}
+		if cacheErrors {
+			opts = append(opts, scraperapi.WithCacheErrorResponses())
+		}
+		client := scraperapi.NewClient(opts...)
+
+		var last *scraperapi.Response
+		for range 2 {
+			res, err := client.Get(context.Background(), "https://example.com", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			last = res
+		}
+
+		want := int32(2)
+		if cacheErrors {
+			want = 1
+		}
+		if hits.Load() != want || last.FromCache() != cacheErrors {
+			t.Errorf("cacheErrors=%v: expected %d requests, got %d (from cache: %v)", cacheErrors, want, hits.Load(), last.FromCache())
+		}
+	}
+}
+
+func TestMemoryCacheEvictsTheLeastRecentlyUsedEntry(t *testing.T) {
+	cache := scraperapi.NewMemoryCache(2)
+	cache.Set("a", scraperapi.CacheEntry{Status: "a"})
+	cache.Set("b", scraperapi.CacheEntry{Status: "b"})
+	cache.Get("a")
+	cache.Set("c", scraperapi.CacheEntry{Status: "c"})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected the least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("expected the recently used entry to be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestDiskCachePersistsEntriesBetweenClients(t *testing.T) {
+	server, hits := newCacheTestServer(t, http.StatusOK)
+	dir := t.TempDir()
+
+	for range 2 {
+		cache, err := scraperapi.NewDiskCache(dir)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithCache(cache))
+		res, err := client.Get(context.Background(), "https://example.com", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.String() != "body of https://example.com" || res.Header().Get("X-Request-Cost") != "1" {
+			t.Fatalf("unexpected response %q, headers %v", res.String(), res.Header())
+		}
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected the second client to be answered from disk, got %d requests", hits.Load())
+	}
+}
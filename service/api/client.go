@@ -2,12 +2,16 @@ package scraperapi
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"slices"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
 	"github.com/zenrows/zenrows-go-sdk/service/api/version"
 )
 
@@ -18,39 +22,71 @@ const (
 
 // Client is the ZenRows Fetch API client
 type Client struct {
-	cfg                  options
-	http                 *resty.Client
-	concurrencySemaphore chan struct{}
+	cfg     options
+	http    *resty.Client
+	limiter *limiter
+	spend   *SpendMeter
+	log     *slog.Logger
+	metrics Metrics
+
+	// handler and attemptHandler are the scrape and send handlers, wrapped by the middleware of the client
+	handler        Handler
+	attemptHandler Handler
+
+	// escalation is the in-memory escalation store used unless one is configured with WithEscalationStore
+	escalation *MemoryEscalationStore
 }
 
 // NewClient creates and returns a new ZenRows Fetch API client
 func NewClient(opts ...Option) *Client {
-	client := &Client{cfg: defaultOptions()}
+	client := &Client{cfg: defaultOptions(), spend: newSpendMeter(), escalation: NewMemoryEscalationStore()}
 
 	for _, opt := range opts {
 		opt.apply(&client.cfg)
 	}
 
-	client.http = resty.New().
+	client.log = newLogger(client.cfg.logger, client.cfg.apiKey)
+	client.metrics = client.cfg.metrics
+	if client.metrics == nil {
+		client.metrics = noopMetrics{}
+	}
+	client.http = newRestyClient(client.cfg.httpClient).
 		SetLogger(noopLogger{}).
 		SetBaseURL(client.cfg.baseURL).
 		SetHeader("User-Agent", "zenrows-go/"+version.Version).
-		SetQueryParam(apiKeyParamName, client.cfg.apiKey).
-		SetRetryCount(client.cfg.retryOptions.maxRetryCount).
-		SetRetryWaitTime(client.cfg.retryOptions.retryWaitTime).
-		SetRetryMaxWaitTime(client.cfg.retryOptions.retryMaxWaitTime).
-		AddRetryCondition(func(r *resty.Response, err error) bool {
-			return err != nil || slices.Contains(retryableStatusCodes, r.StatusCode())
-		})
+		SetQueryParam(apiKeyParamName, client.cfg.apiKey)
+
+	// if the maxConcurrentRequests is set, the limiter limits the number of concurrent requests; otherwise it only counts them
+	client.limiter = newLimiter(client.cfg.maxConcurrentRequests)
 
-	// if the maxConcurrentRequests is set, create a semaphore to limit the number of concurrent requests
-	if client.cfg.maxConcurrentRequests > 0 {
-		client.concurrencySemaphore = make(chan struct{}, client.cfg.maxConcurrentRequests)
+	// in adaptive mode, the limiter follows the concurrency headers of every response, so it starts limited anyway
+	if client.cfg.adaptiveConcurrency {
+		if client.cfg.maxConcurrentRequests <= 0 {
+			client.limiter = newLimiter(defaultAdaptiveConcurrency)
+		}
+		client.http.SetTransport(&adaptiveTransport{
+			base:    client.http.GetClient().Transport,
+			limiter: client.limiter,
+			retry:   client.cfg.retryOptions,
+			log:     client.log,
+		})
 	}
 
+	client.handler = chainMiddleware(client.scrape, client.cfg.middleware)
+	client.attemptHandler = chainMiddleware(client.send, client.cfg.attemptMiddleware)
+
 	return client
 }
 
+// newRestyClient returns a resty client sending requests with a copy of httpClient, or with a default client if it is nil.
+func newRestyClient(httpClient *http.Client) *resty.Client {
+	if httpClient == nil {
+		return resty.New()
+	}
+	hc := *httpClient
+	return resty.NewWithClient(&hc)
+}
+
 // isConfigured returns true if the client is configured with a base url and a secret key
 func (c *Client) isConfigured() bool {
 	return c.cfg.baseURL != "" && c.cfg.apiKey != ""
@@ -58,53 +94,156 @@ func (c *Client) isConfigured() bool {
 
 // Scrape sends a request to the ZenRows Fetch API to scrape the given target URL using the specified method and parameters.
 func (c *Client) Scrape(ctx context.Context, method, targetURL string, params *RequestParameters, body any) (*Response, error) {
+	return c.handler(ctx, &Request{Method: method, TargetURL: targetURL, Params: params, Body: body})
+}
+
+// scrape is the handler wrapped by the middleware of the client (see WithMiddleware): it validates the request, answers it from the
+// cache if possible, and otherwise sends it, retrying it as the retry policy says.
+func (c *Client) scrape(ctx context.Context, req *Request) (*Response, error) {
+	resolvedURL, err := c.validateRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	resolved := *req
+	resolved.TargetURL = resolvedURL
+
+	// cached responses are served before the budget and the concurrency slot, as they cost nothing
+	cache, key, cached := c.cacheFor(ctx, &resolved)
+	if cached {
+		if response, ok := cachedResponse(cache, key, &resolved); ok {
+			c.log.DebugContext(ctx, "scraperapi: response served from cache",
+				slog.String("method", resolved.Method), slog.String("target_url", resolved.TargetURL))
+			return response, nil
+		}
+	}
+
+	// reserve the worst-case cost of the first attempt from the budgets, if any, so a request that does not fit is rejected before
+	// waiting for a slot; executeWithRetry settles it with the actual cost, and reserves again for every retry
+	settle, err := c.reserveBudget(ctx, req.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	// wait for a concurrency slot before sending the request; streamed responses hold it until they are closed
+	queuedAt := time.Now()
+	release, err := c.limiter.acquire(ctx)
+	c.metrics.ObserveDuration(MetricQueueWait, nil, time.Since(queuedAt))
+	if err != nil {
+		settle(nil)
+		return nil, err
+	}
+
+	// execute the request, retrying it as the retry policy says, and return the response or an error if one occurred
+	response, err := c.executeWithRetry(ctx, &resolved, settle)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	if cached {
+		c.storeResponse(ctx, cache, key, response)
+	}
+
+	// the body of streamed error responses is already buffered and closed, see send
+	if !req.Stream || response.IsError() {
+		release()
+		return response, nil
+	}
+	return c.streamResponse(response, release)
+}
+
+// send is the handler wrapped by the attempt middleware of the client (see WithAttemptMiddleware): it sends a single attempt of a
+// request. For streamed requests, the body of error responses is buffered (up to maxStreamErrorBodySize), so their problem can be
+// parsed.
+func (c *Client) send(ctx context.Context, req *Request) (*Response, error) {
+	r := c.http.R().SetContext(ctx).SetQueryParam(urlParamName, req.TargetURL).SetBody(req.Body).SetDoNotParseResponse(req.Stream)
+	if req.Params != nil {
+		r.SetHeaderMultiValues(req.Params.CustomHeaders)
+		r.SetQueryParamsFromValues(req.Params.ToURLValues())
+	}
+
+	attrs := []any{slog.String("method", req.Method), slog.String("target_url", req.TargetURL), slog.Int("attempt", req.Attempt)}
+	c.log.DebugContext(ctx, "scraperapi: request start", append(attrs, slog.Bool("stream", req.Stream))...)
+
+	sentAt := time.Now()
+	res, err := r.Execute(req.Method, "/")
+	c.metrics.ObserveDuration(MetricRequestDuration, map[string]string{"method": req.Method}, time.Since(sentAt))
+	if err != nil {
+		if res != nil && req.Stream && res.RawBody() != nil {
+			_ = res.RawBody().Close()
+		}
+		c.metrics.AddCounter(MetricRequests, map[string]string{"method": req.Method, "status": "error"}, 1)
+		c.log.WarnContext(ctx, "scraperapi: request failed", append(attrs, slog.Any("error", err))...)
+		return nil, err
+	}
+	c.metrics.AddCounter(MetricRequests, map[string]string{"method": req.Method, "status": strconv.Itoa(res.StatusCode())}, 1)
+	c.log.DebugContext(ctx, "scraperapi: request end", append(attrs,
+		slog.Int("status", res.StatusCode()),
+		slog.Duration("duration", res.Time()),
+		slog.String("request_id", res.Header().Get(requestIDHeader)),
+		slog.String("cost", res.Header().Get(requestCostHeader)),
+	)...)
+
+	if req.Stream && res.IsError() {
+		if err = bufferErrorBody(res); err != nil {
+			return nil, err
+		}
+	}
+	return &Response{res: res, targetURL: req.TargetURL, params: req.Params}, nil
+}
+
+// afterResponse records the response of an attempt in the limiter, the spend meter and the metrics.
+func (c *Client) afterResponse(ctx context.Context, response *Response) {
+	c.limiter.observe(response.Time())
+	c.spend.record(tagFromContext(ctx), response)
+	if cost := response.Cost(); cost.Reported {
+		c.metrics.AddCounter(MetricCredits, nil, cost.Credits)
+	}
+}
+
+// validateRequest validates the method, target URL and parameters of a request to send to the ZenRows Fetch API. It returns the
+// normalized target URL.
+func (c *Client) validateRequest(req *Request) (string, error) {
 	// make sure the client is configured before sending the request
 	if !c.isConfigured() {
-		return nil, NotConfiguredError{}
+		return "", NotConfiguredError{}
 	}
 
 	// make sure the method is valid
-	if !slices.Contains(validHTTPMethods, method) {
-		return nil, InvalidHTTPMethodError{}
+	if !slices.Contains(validHTTPMethods, req.Method) {
+		return "", InvalidHTTPMethodError{}
 	}
 
 	// make sure a target url is provided
-	if targetURL == "" {
-		return nil, InvalidTargetURLError{Msg: "target url cannot be empty"}
+	if req.TargetURL == "" {
+		return "", InvalidTargetURLError{Msg: "target url cannot be empty"}
 	}
 
 	// make sure the target url is a valid url
-	parsedURL, parseErr := url.Parse(targetURL)
+	parsedURL, parseErr := url.Parse(req.TargetURL)
 	if parseErr != nil {
-		return nil, InvalidTargetURLError{URL: targetURL, Err: parseErr}
+		return "", InvalidTargetURLError{URL: req.TargetURL, Err: parseErr}
 	}
 
-	// create the request
-	req := c.http.R().SetContext(ctx).SetQueryParam(urlParamName, parsedURL.String()).SetBody(body)
-
-	// if parameters are provided, validate them and set them on the request
-	if params != nil {
-		if err := params.Validate(); err != nil {
-			return nil, err
+	// if parameters are provided, validate them
+	if req.Params != nil {
+		if err := req.Params.Validate(); err != nil {
+			return "", err
 		}
-
-		req.SetHeaderMultiValues(params.CustomHeaders)
-		req.SetQueryParamsFromValues(params.ToURLValues())
 	}
 
-	// if the concurrency semaphore is initialized, acquire a token before sending the request
-	// and release it after the request is done
-	if c.concurrencySemaphore != nil {
-		c.concurrencySemaphore <- struct{}{}
-		defer func() { <-c.concurrencySemaphore }()
-	}
+	return parsedURL.String(), nil
+}
 
-	// execute the request, and return the response or an error if one occurred
-	res, err := req.Execute(method, "/")
-	if err != nil {
-		return nil, err
-	}
-	return &Response{res: res}, nil
+// Concurrency returns a snapshot of the requests in flight and waiting for a concurrency slot, e.g. to tell when the client is
+// saturating the concurrency of your plan.
+func (c *Client) Concurrency() ConcurrencyStats {
+	return c.limiter.stats()
+}
+
+// Spend returns the spend meter of the client, which aggregates the credits charged for every request it sends.
+func (c *Client) Spend() *SpendMeter {
+	return c.spend
 }
 
 // Get sends an HTTP GET request to the ZenRows Fetch API to scrape the given target URL using the specified parameters.
@@ -158,9 +297,14 @@ func (c *Client) Extract(ctx context.Context, targetURL string, params *RequestP
 		autoparseParams := extractParams
 		autoparseParams.Extract = ""
 		autoparseParams.AutoParse = true
-		return c.Get(ctx, targetURL, &autoparseParams)
+		fallback, fallbackErr := c.Get(ctx, targetURL, &autoparseParams)
+		if fallback != nil {
+			fallback.extractSource = ExtractSourceAutoParse
+		}
+		return fallback, fallbackErr
 	}
 
+	response.extractSource = ExtractSourceExtract
 	return response, nil
 }
 
@@ -178,8 +322,7 @@ func withCustomParam(params map[string]string, key, value string) map[string]str
 // isAuth010 reports whether a response's problem envelope carries the Extract
 // domain-not-enabled code (AUTH010).
 func isAuth010(response *Response) bool {
-	prob := response.Problem()
-	return prob != nil && strings.EqualFold(prob.Code, "AUTH010")
+	return errors.Is(response.Error(), problem.ErrDomainNotEnabled)
 }
 
 // Post sends an HTTP POST request to the ZenRows Fetch API to scrape the given target URL using the specified parameters.
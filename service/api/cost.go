@@ -0,0 +1,204 @@
+package scraperapi
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// requestCostHeader is the response header with the credits charged for the request.
+	requestCostHeader = "X-Request-Cost"
+	// requestIDHeader is the response header with the ID of the request, to refer to it with ZenRows support.
+	requestIDHeader = "X-Request-Id"
+)
+
+// Credits charged per successful request, by configuration. ModeAuto is charged dynamically post-factum, anywhere in the Auto
+// range, depending on the configuration Adaptive Stealth Mode ends up using.
+const (
+	BaseCredits         = 1
+	JSCredits           = 5
+	PremiumProxyCredits = 10
+	JSAndProxyCredits   = 25
+	AutoMinCredits      = 1
+	AutoMaxCredits      = 25
+)
+
+// Tier is the pricing tier of a request: the configuration it is charged for.
+type Tier string
+
+const (
+	TierBase         Tier = "base"
+	TierJS           Tier = "js_render"
+	TierPremium      Tier = "premium_proxy"
+	TierJSAndPremium Tier = "js_render+premium_proxy"
+	// TierAuto is the tier of ModeAuto requests, when the configuration Adaptive Stealth Mode used is not known.
+	TierAuto Tier = "auto"
+)
+
+// AllTiers is a set of all the pricing tiers.
+var AllTiers = map[Tier]struct{}{
+	TierBase:         {},
+	TierJS:           {},
+	TierPremium:      {},
+	TierJSAndPremium: {},
+	TierAuto:         {},
+}
+
+// tierByCredits maps the credits charged for a request to the tier it was charged for, to tell which configuration ModeAuto used.
+var tierByCredits = map[float64]Tier{
+	BaseCredits:         TierBase,
+	JSCredits:           TierJS,
+	PremiumProxyCredits: TierPremium,
+	JSAndProxyCredits:   TierJSAndPremium,
+}
+
+// Credits returns the credits charged for a successful request of the tier, as a range for TierAuto.
+func (t Tier) Credits() (unitMin, unitMax int) {
+	switch t {
+	case TierJS:
+		return JSCredits, JSCredits
+	case TierPremium:
+		return PremiumProxyCredits, PremiumProxyCredits
+	case TierJSAndPremium:
+		return JSAndProxyCredits, JSAndProxyCredits
+	case TierAuto:
+		return AutoMinCredits, AutoMaxCredits
+	default:
+		return BaseCredits, BaseCredits
+	}
+}
+
+// Cost is what a request was charged, as reported by the ZenRows Fetch API.
+type Cost struct {
+	// Credits is the number of credits charged for the request. It is 0 if the response does not report it.
+	Credits float64
+	// Reported is true if the response reports the credits charged.
+	Reported bool
+	// Tier is the configuration the request was charged for. For ModeAuto requests, it is the configuration Adaptive Stealth
+	// Mode ended up using, as told by the credits charged, or TierAuto if the response does not tell.
+	Tier Tier
+	// Auto is true if the request was sent with ModeAuto.
+	Auto bool
+	// RequestID is the ID of the request, to refer to it with ZenRows support.
+	RequestID string
+}
+
+// Cost method returns what the request was charged, read from the X-Request-Cost response header.
+func (r *Response) Cost() Cost {
+	cost := Cost{RequestID: r.Header().Get(requestIDHeader)}
+	cost.Tier, cost.Auto = r.params.tier()
+
+	if credits, err := strconv.ParseFloat(strings.TrimSpace(r.Header().Get(requestCostHeader)), 64); err == nil {
+		cost.Credits, cost.Reported = credits, true
+		if tier, ok := tierByCredits[credits]; ok && cost.Auto {
+			cost.Tier = tier
+		}
+	}
+
+	return cost
+}
+
+// tier returns the pricing tier of a request sent with the parameters, and whether it uses ModeAuto. The parameters can be nil.
+func (p *RequestParameters) tier() (tier Tier, auto bool) {
+	if p == nil {
+		return TierBase, false
+	}
+
+	if p.Mode == ModeAuto || strings.EqualFold(strings.TrimSpace(p.CustomParams["mode"]), string(ModeAuto)) {
+		return TierAuto, true
+	}
+
+	js := p.JSRender || truthy(p.CustomParams["js_render"])
+	premium := p.UsePremiumProxies || truthy(p.CustomParams["premium_proxy"])
+	switch {
+	case js && premium:
+		return TierJSAndPremium, false
+	case premium:
+		return TierPremium, false
+	case js:
+		return TierJS, false
+	default:
+		return TierBase, false
+	}
+}
+
+// truthyStrings are the spellings of true the billing engine accepts for boolean parameters.
+var truthyStrings = map[string]struct{}{"true": {}, "1": {}, "yes": {}, "on": {}}
+
+// truthy reports whether a custom parameter value is one of truthyStrings, case-insensitively.
+func truthy(value string) bool {
+	_, ok := truthyStrings[strings.ToLower(strings.TrimSpace(value))]
+	return ok
+}
+
+// CostLine is one row of the breakdown of a CostEstimate: all the requests sharing a tier, aggregated.
+type CostLine struct {
+	Tier    Tier
+	Count   int
+	UnitMin int
+	UnitMax int
+}
+
+// SubtotalMin is Count * UnitMin.
+func (l CostLine) SubtotalMin() int { return l.Count * l.UnitMin }
+
+// SubtotalMax is Count * UnitMax.
+func (l CostLine) SubtotalMax() int { return l.Count * l.UnitMax }
+
+// Exact is true when UnitMin == UnitMax (every request in this tier prices identically).
+func (l CostLine) Exact() bool { return l.UnitMin == l.UnitMax }
+
+// CostEstimate is the result of EstimateCost: the credits charged assuming every request succeeds once. Exact() is true when no
+// request uses ModeAuto.
+//
+// Unsuccessful requests are not charged, unless their status code is one of the AllowedStatusCodes: those are listed in
+// ChargedStatusCodes, and are charged like successful requests, so the estimate holds whether requests succeed or fail with them.
+type CostEstimate struct {
+	Requests  int
+	Min       int
+	Max       int
+	Breakdown []CostLine
+	// ChargedStatusCodes are the unsuccessful status codes charged like successful requests (see AllowedStatusCodes).
+	ChargedStatusCodes []int
+}
+
+// Exact is true when the charge is a single number (no ModeAuto requests).
+func (e CostEstimate) Exact() bool { return e.Min == e.Max }
+
+// AutoRequests is how many requests use ModeAuto — the only source of range.
+func (e CostEstimate) AutoRequests() int {
+	for _, line := range e.Breakdown {
+		if line.Tier == TierAuto {
+			return line.Count
+		}
+	}
+	return 0
+}
+
+// ChargesUnsuccessful is true when unsuccessful requests may be charged too, because of AllowedStatusCodes.
+func (e CostEstimate) ChargesUnsuccessful() bool { return len(e.ChargedStatusCodes) > 0 }
+
+// EstimateCost estimates the credit cost of sending n requests with the given parameters, which can be nil, before sending them.
+// Pure and offline — no network call. It uses the same pricing tiers as the Batch API SDK's EstimateCost.
+func EstimateCost(params *RequestParameters, n int) CostEstimate {
+	estimate := CostEstimate{Requests: max(n, 0)}
+	if params != nil {
+		for _, code := range params.AllowedStatusCodes {
+			if code >= http.StatusMultipleChoices && !slices.Contains(estimate.ChargedStatusCodes, code) {
+				estimate.ChargedStatusCodes = append(estimate.ChargedStatusCodes, code)
+			}
+		}
+		slices.Sort(estimate.ChargedStatusCodes)
+	}
+	if estimate.Requests == 0 {
+		return estimate
+	}
+
+	tier, _ := params.tier()
+	unitMin, unitMax := tier.Credits()
+	line := CostLine{Tier: tier, Count: estimate.Requests, UnitMin: unitMin, UnitMax: unitMax}
+	estimate.Min, estimate.Max, estimate.Breakdown = line.SubtotalMin(), line.SubtotalMax(), []CostLine{line}
+	return estimate
+}
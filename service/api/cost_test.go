@@ -0,0 +1,121 @@
+package scraperapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+func TestResponseCostReadsRequestCostHeader(t *testing.T) {
+	params := &scraperapi.RequestParameters{JSRender: true}
+	res := doGetWithParams(t, params, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Cost", "5")
+		w.Header().Set("X-Request-Id", "req-1")
+	})
+
+	cost := res.Cost()
+	if !cost.Reported || cost.Credits != 5 || cost.Tier != scraperapi.TierJS || cost.Auto || cost.RequestID != "req-1" {
+		t.Fatalf("unexpected cost: %+v", cost)
+	}
+}
+
+func TestResponseCostTellsWhichConfigurationModeAutoUsed(t *testing.T) {
+	params := &scraperapi.RequestParameters{Mode: scraperapi.ModeAuto}
+	res := doGetWithParams(t, params, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Cost", "10")
+	})
+
+	if cost := res.Cost(); !cost.Auto || cost.Tier != scraperapi.TierPremium {
+		t.Fatalf("expected ModeAuto to resolve to the premium proxy tier, got %+v", cost)
+	}
+
+	res = doGetWithParams(t, params, func(w http.ResponseWriter, r *http.Request) {})
+	if cost := res.Cost(); cost.Reported || cost.Tier != scraperapi.TierAuto {
+		t.Fatalf("expected an unresolved auto tier without the header, got %+v", cost)
+	}
+}
+
+func TestSpendMeterAggregatesByTagStatusAndTier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("url") == "https://example.com/missing" {
+			w.Header().Set("X-Request-Cost", "0")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("X-Request-Cost", "25")
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"))
+	crawler := scraperapi.ContextWithTag(context.Background(), "crawler")
+	params := &scraperapi.RequestParameters{JSRender: true, UsePremiumProxies: true}
+
+	for _, target := range []string{"https://example.com/a", "https://example.com/b", "https://example.com/missing"} {
+		if _, err := client.Get(crawler, target, params); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := client.Get(context.Background(), "https://example.com/c", params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot := client.Spend().Snapshot()
+	if snapshot.Total != (scraperapi.SpendTotal{Requests: 4, Credits: 75}) {
+		t.Fatalf("unexpected total: %+v", snapshot.Total)
+	}
+	if snapshot.ByTag["crawler"] != (scraperapi.SpendTotal{Requests: 3, Credits: 50}) || snapshot.ByTag[""].Credits != 25 {
+		t.Fatalf("unexpected spend by tag: %+v", snapshot.ByTag)
+	}
+	if snapshot.ByStatus[http.StatusNotFound] != (scraperapi.SpendTotal{Requests: 1}) || snapshot.ByStatus[http.StatusOK].Requests != 3 {
+		t.Fatalf("unexpected spend by status: %+v", snapshot.ByStatus)
+	}
+	if snapshot.ByTier[scraperapi.TierJSAndPremium].Requests != 4 {
+		t.Fatalf("unexpected spend by tier: %+v", snapshot.ByTier)
+	}
+
+	client.Spend().Reset()
+	if snapshot := client.Spend().Snapshot(); snapshot.Total.Requests != 0 || len(snapshot.ByTag) != 0 {
+		t.Fatalf("expected Reset to clear the meter, got %+v", snapshot)
+	}
+}
+
+func TestEstimateCostTiers(t *testing.T) {
+	premium := map[string]string{"premium_proxy": " On "}
+	tests := []struct {
+		params   *scraperapi.RequestParameters
+		tier     scraperapi.Tier
+		min, max int
+	}{
+		{nil, scraperapi.TierBase, 3 * scraperapi.BaseCredits, 3 * scraperapi.BaseCredits},
+		{&scraperapi.RequestParameters{JSRender: true}, scraperapi.TierJS, 3 * scraperapi.JSCredits, 3 * scraperapi.JSCredits},
+		{&scraperapi.RequestParameters{UsePremiumProxies: true}, scraperapi.TierPremium, 30, 30},
+		{&scraperapi.RequestParameters{JSRender: true, CustomParams: premium}, scraperapi.TierJSAndPremium, 75, 75},
+		{&scraperapi.RequestParameters{Mode: scraperapi.ModeAuto}, scraperapi.TierAuto, 3, 75},
+	}
+
+	for _, tt := range tests {
+		est := scraperapi.EstimateCost(tt.params, 3)
+		if est.Requests != 3 || est.Min != tt.min || est.Max != tt.max || len(est.Breakdown) != 1 || est.Breakdown[0].Tier != tt.tier {
+			t.Errorf("unexpected estimate for %s: %+v", tt.tier, est)
+		}
+	}
+
+	if est := scraperapi.EstimateCost(&scraperapi.RequestParameters{Mode: scraperapi.ModeAuto}, 2); est.Exact() || est.AutoRequests() != 2 {
+		t.Fatalf("expected a ranged estimate for ModeAuto, got %+v", est)
+	}
+}
+
+func TestEstimateCostAccountsForAllowedStatusCodes(t *testing.T) {
+	if est := scraperapi.EstimateCost(nil, 1); est.ChargesUnsuccessful() {
+		t.Fatalf("expected unsuccessful requests not to be charged by default, got %+v", est)
+	}
+
+	params := &scraperapi.RequestParameters{AllowedStatusCodes: []int{404, 200, 500, 404}}
+	est := scraperapi.EstimateCost(params, 1)
+	if !est.ChargesUnsuccessful() || len(est.ChargedStatusCodes) != 2 || est.ChargedStatusCodes[0] != 404 || est.ChargedStatusCodes[1] != 500 {
+		t.Fatalf("unexpected charged status codes: %v", est.ChargedStatusCodes)
+	}
+}
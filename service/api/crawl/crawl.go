@@ -0,0 +1,206 @@
+// Package crawl implements a crawler on top of the ZenRows Fetch API client: starting from a set of seeds, it fetches every page
+// with scraperapi.Client.Get, discovers the links of HTML pages and follows those in scope, up to a maximum depth and number of
+// pages. Every fetched page is a billed request, so mind the limits.
+package crawl
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strings"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+// Client is the subset of scraperapi.Client used by a Crawler, so it can be fed a fake in tests. scraperapi.Client and
+// scraperapitest.MockClient implement it.
+type Client interface {
+	// Get sends a GET request to the ZenRows Fetch API to scrape the given target URL (see scraperapi.Client.Get).
+	Get(ctx context.Context, targetURL string, params *scraperapi.RequestParameters) (*scraperapi.Response, error)
+	// Concurrency returns a snapshot of the concurrency limiter of the client (see scraperapi.Client.Concurrency).
+	Concurrency() scraperapi.ConcurrencyStats
+}
+
+// scraperapi.Client must implement Client.
+var _ Client = (*scraperapi.Client)(nil)
+
+// Crawler crawls websites through the ZenRows Fetch API. It is safe to run several crawls with the same Crawler concurrently.
+type Crawler struct {
+	client Client
+	cfg    options
+}
+
+// Page is a page fetched during a crawl.
+type Page struct {
+	// URL is the canonical URL of the page.
+	URL string
+	// Depth is the number of links followed from a seed to reach the page (0 for the seeds themselves).
+	Depth int
+	// Referrer is the canonical URL of the page the link to this one was first found on. It is empty for the seeds.
+	Referrer string
+	// Params are the request parameters the page was fetched with, as returned by the ParamsFunc.
+	Params *scraperapi.RequestParameters
+	// Response is the response of the ZenRows Fetch API. It is nil when the request failed.
+	Response *scraperapi.Response
+	// Links are the canonical http and https links found on the page, whether in scope or not. Only HTML responses that are not
+	// errors are searched for links.
+	Links []string
+}
+
+// New creates and returns a new Crawler that fetches pages with the given client.
+//
+// The crawler sends as many requests at a time as the client allows when a crawl starts (see Client.Concurrency), whether
+// the limit was set with scraperapi.WithMaxConcurrentRequests or learned with scraperapi.WithAdaptiveConcurrency, sharing it with any
+// other request sent through the same client. If the client is not limited, it sends 5 at a time.
+func New(client Client, opts ...Option) *Crawler {
+	crawler := &Crawler{client: client, cfg: defaultOptions()}
+
+	for _, opt := range opts {
+		opt.apply(&crawler.cfg)
+	}
+
+	return crawler
+}
+
+// Crawl crawls from the given seeds, and yields every fetched page along with the error of its request, if any, in the order the
+// pages are fetched. Error responses (see scraperapi.Response.IsError) are yielded with a nil error, as the crawl goes on; check
+// Page.Response. Stopping the iteration cancels the requests in flight.
+//
+// Seeds are fetched regardless of the scope. If a seed is not a valid http or https URL, a single scraperapi.InvalidTargetURLError
+// is yielded and nothing is fetched.
+func (c *Crawler) Crawl(ctx context.Context, seeds ...string) iter.Seq2[*Page, error] {
+	return func(yield func(*Page, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		f, err := c.newFrontier(seeds)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		concurrency := c.client.Concurrency().Limit
+		if concurrency <= 0 {
+			concurrency = defaultConcurrency
+		}
+
+		// every request sends exactly one result, and at most concurrency requests are in flight, so sending never blocks and
+		// requests left in flight when the iteration stops can finish in the background
+		results := make(chan fetchResult, concurrency)
+		inFlight, fetched := 0, 0
+		for {
+			for inFlight < concurrency && fetched < c.cfg.maxPages && len(f.queue) > 0 {
+				page := f.pop()
+				inFlight++
+				fetched++
+				go func() { results <- c.fetch(ctx, page) }()
+			}
+			if inFlight == 0 {
+				return
+			}
+
+			result := <-results
+			inFlight--
+			if result.err == nil && result.page.Depth < c.cfg.maxDepth {
+				for _, link := range result.links {
+					f.push(link, result.page)
+				}
+			}
+
+			if !yield(result.page, result.err) {
+				return
+			}
+		}
+	}
+}
+
+// fetchResult is the outcome of fetching a page.
+type fetchResult struct {
+	page  *Page
+	links []*url.URL
+	err   error
+}
+
+// fetch fetches a page and discovers its links.
+func (c *Crawler) fetch(ctx context.Context, page *Page) fetchResult {
+	target, _ := url.Parse(page.URL) // page urls are canonical, so they always parse
+	if c.cfg.params != nil {
+		page.Params = c.cfg.params(target, page.Depth)
+	}
+
+	response, err := c.client.Get(ctx, page.URL, page.Params)
+	if err != nil {
+		return fetchResult{page: page, err: err}
+	}
+	page.Response = response
+
+	var links []*url.URL
+	if contentType := response.Header().Get("Content-Type"); !response.IsError() && (contentType == "" || isHTML(contentType)) {
+		links = extractLinks(target, response.Body())
+	}
+	for _, link := range links {
+		page.Links = append(page.Links, link.String())
+	}
+
+	return fetchResult{page: page, links: links}
+}
+
+// frontier holds the pages left to fetch in a crawl, and every URL seen so far.
+type frontier struct {
+	queue   []*Page
+	seen    map[string]struct{}
+	inScope ScopeFunc
+}
+
+// newFrontier returns the frontier of a crawl starting from the given seeds.
+func (c *Crawler) newFrontier(seeds []string) (*frontier, error) {
+	f := &frontier{seen: make(map[string]struct{}), inScope: c.cfg.scope}
+
+	domains := c.cfg.allowedDomains
+	for _, seed := range seeds {
+		canonical, err := Canonicalize(seed)
+		if err != nil {
+			return nil, scraperapi.InvalidTargetURLError{URL: seed, Msg: "invalid seed url", Err: err}
+		}
+
+		if _, dup := f.seen[canonical]; !dup {
+			f.seen[canonical] = struct{}{}
+			f.queue = append(f.queue, &Page{URL: canonical})
+		}
+		if len(c.cfg.allowedDomains) == 0 {
+			seedURL, _ := url.Parse(canonical)
+			domains = append(domains, strings.TrimPrefix(seedURL.Hostname(), "www."))
+		}
+	}
+
+	if f.inScope == nil {
+		f.inScope = func(target *url.URL) bool {
+			for _, domain := range domains {
+				if domainMatches(target.Hostname(), domain) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	return f, nil
+}
+
+// pop removes and returns the next page to fetch.
+func (f *frontier) pop() *Page {
+	page := f.queue[0]
+	f.queue = f.queue[1:]
+	return page
+}
+
+// push adds a link found on referrer to the queue, unless it was already seen or it is out of scope.
+func (f *frontier) push(link *url.URL, referrer *Page) {
+	key := link.String()
+	if _, dup := f.seen[key]; dup || !f.inScope(link) {
+		return
+	}
+
+	f.seen[key] = struct{}{}
+	f.queue = append(f.queue, &Page{URL: key, Depth: referrer.Depth + 1, Referrer: referrer.URL})
+}
@@ -0,0 +1,277 @@
+package crawl_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/crawl"
+	"github.com/zenrows/zenrows-go-sdk/service/api/scraperapitest"
+)
+
+// site is a fake ZenRows Fetch API serving the given pages, keyed by target url. It records the requests it receives.
+type site struct {
+	pages map[string]string
+
+	mu       sync.Mutex
+	requests []url.Values
+}
+
+func (s *site) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.Query())
+	s.mu.Unlock()
+
+	body, ok := s.pages[r.URL.Query().Get("url")]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(body))
+}
+
+func (s *site) targets() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var targets []string
+	for _, query := range s.requests {
+		targets = append(targets, query.Get("url"))
+	}
+	slices.Sort(targets)
+	return targets
+}
+
+func newCrawler(t *testing.T, s *site, opts ...crawl.Option) *crawl.Crawler {
+	t.Helper()
+
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithMaxConcurrentRequests(2))
+	return crawl.New(client, opts...)
+}
+
+var testSite = map[string]string{
+	"https://example.com/": `<a href="/a">A</a> <a href="b#top">B</a> <a href="https://other.com/">Other</a>
+		<a href="mailto:info@example.com">Mail</a>`,
+	"https://example.com/a":              `<a href="/">Home</a> <a href="/a/deep?y=2&x=1">Deep</a>`,
+	"https://example.com/b":              `<base href="https://blog.example.com/posts/"><a href="../about">About</a>`,
+	"https://blog.example.com/about":     `<a href="https://example.com/b">B</a>`,
+	"https://example.com/a/deep?x=1&y=2": `<a href="/deeper">Deeper</a>`,
+	"https://other.com/":                 ``,
+}
+
+func TestCrawlFollowsLinksInScopeUpToMaxDepth(t *testing.T) {
+	s := &site{pages: testSite}
+	crawler := newCrawler(t, s, crawl.WithMaxDepth(2))
+
+	pages := make(map[string]*crawl.Page)
+	for page, err := range crawler.Crawl(context.Background(), "https://EXAMPLE.com") {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		pages[page.URL] = page
+	}
+
+	want := []string{
+		"https://blog.example.com/about",
+		"https://example.com/",
+		"https://example.com/a",
+		"https://example.com/a/deep?x=1&y=2",
+		"https://example.com/b",
+	}
+	if got := s.targets(); !slices.Equal(got, want) {
+		t.Fatalf("expected every in-scope page to be fetched once, got %v", got)
+	}
+
+	about := pages["https://blog.example.com/about"]
+	if about.Depth != 2 || about.Referrer != "https://example.com/b" {
+		t.Fatalf("unexpected depth/referrer: %d %q", about.Depth, about.Referrer)
+	}
+	wantLinks := []string{"https://example.com/a", "https://example.com/b", "https://other.com/"}
+	if home := pages["https://example.com/"]; !slices.Equal(home.Links, wantLinks) {
+		t.Fatalf("unexpected links: %v", home.Links)
+	}
+}
+
+func TestCrawlStopsAtMaxPages(t *testing.T) {
+	s := &site{pages: testSite}
+	crawler := newCrawler(t, s, crawl.WithMaxPages(2))
+
+	var count int
+	for range crawler.Crawl(context.Background(), "https://example.com/") {
+		count++
+	}
+	if count != 2 || len(s.targets()) != 2 {
+		t.Fatalf("expected 2 pages to be fetched, got %d (%d requests)", count, len(s.targets()))
+	}
+}
+
+func TestCrawlWithAllowedDomainsAndParams(t *testing.T) {
+	s := &site{pages: testSite}
+	crawler := newCrawler(t, s,
+		crawl.WithMaxDepth(1),
+		crawl.WithAllowedDomains("example.com", "other.com"),
+		crawl.WithParams(func(target *url.URL, depth int) *scraperapi.RequestParameters {
+			if depth == 0 {
+				return &scraperapi.RequestParameters{JSRender: true}
+			}
+			return nil
+		}),
+	)
+
+	for _, err := range crawler.Crawl(context.Background(), "https://example.com/") {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := s.targets(); !slices.Contains(got, "https://other.com/") || len(got) != 4 {
+		t.Fatalf("expected the allowlisted domain to be crawled, got %v", got)
+	}
+	for _, query := range s.requests {
+		wantJSRender := query.Get("url") == "https://example.com/"
+		if (query.Get("js_render") == "true") != wantJSRender {
+			t.Fatalf("unexpected js_render for %s: %q", query.Get("url"), query.Get("js_render"))
+		}
+	}
+}
+
+func TestCrawlYieldsErrorResponsesAndStopsOnBreak(t *testing.T) {
+	s := &site{pages: map[string]string{"https://example.com/": `<a href="/missing">Missing</a> <a href="/other">Other</a>`}}
+	crawler := newCrawler(t, s)
+
+	var statuses []int
+	for page, err := range crawler.Crawl(context.Background(), "https://example.com/") {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		statuses = append(statuses, page.Response.StatusCode())
+		if page.Response.IsError() {
+			break
+		}
+	}
+	if len(statuses) != 2 || statuses[1] != http.StatusNotFound {
+		t.Fatalf("expected the crawl to stop at the first error response, got %v", statuses)
+	}
+}
+
+func TestCrawlRejectsInvalidSeeds(t *testing.T) {
+	crawler := newCrawler(t, &site{})
+
+	for page, err := range crawler.Crawl(context.Background(), "ftp://example.com") {
+		var invalid scraperapi.InvalidTargetURLError
+		if page != nil || !errors.As(err, &invalid) {
+			t.Fatalf("expected InvalidTargetURLError, got %v", err)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := map[string]string{
+		"HTTP://Example.COM":                   "http://example.com/",
+		"https://example.com:443/a/./b/../c":   "https://example.com/a/c",
+		"http://example.com:8080/?b=2&a=1#top": "http://example.com:8080/?a=1&b=2",
+		"https://example.com/path/":            "https://example.com/path/",
+	}
+	for in, want := range tests {
+		got, err := crawl.Canonicalize(in)
+		if err != nil || got != want {
+			t.Errorf("Canonicalize(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"/relative", "javascript:void(0)", "mailto:a@b.c"} {
+		if _, err := crawl.Canonicalize(in); err == nil {
+			t.Errorf("expected Canonicalize(%q) to fail", in)
+		}
+	}
+}
+
+func TestCrawlUsesTheConcurrencyLearnedByTheClient(t *testing.T) {
+	const planLimit = 8
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		w.Header().Set("Concurrency-Limit", strconv.Itoa(planLimit))
+		w.Header().Set("Concurrency-Remaining", strconv.Itoa(planLimit-inFlight))
+		mu.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+		body := ""
+		if r.URL.Query().Get("url") == "https://example.com/" {
+			for i := range planLimit {
+				body += `<a href="/` + strconv.Itoa(i) + `">page</a>`
+			}
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(body))
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithAdaptiveConcurrency())
+	if _, err := client.Get(context.Background(), "https://example.com/learn", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limit := client.Concurrency().Limit; limit != planLimit {
+		t.Fatalf("expected the client to learn the limit of the plan, got %d", limit)
+	}
+
+	mu.Lock()
+	maxInFlight = 0
+	mu.Unlock()
+	for _, err := range crawl.New(client, crawl.WithMaxDepth(1)).Crawl(context.Background(), "https://example.com/") {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxInFlight <= 5 {
+		t.Fatalf("expected the crawl to use the learned limit of %d, got at most %d requests at a time", planLimit, maxInFlight)
+	}
+}
+
+func TestCrawlAcceptsMockClient(t *testing.T) {
+	mock := &scraperapitest.MockClient{
+		ScrapeFunc: func(_ context.Context, _, targetURL string, _ *scraperapi.RequestParameters, _ any) (*scraperapi.Response, error) {
+			header := http.Header{"Content-Type": {"text/html"}}
+			if targetURL == "https://example.com/" {
+				return scraperapi.NewResponse(http.StatusOK, header, []byte(`<a href="/about">About</a>`)), nil
+			}
+			return scraperapi.NewResponse(http.StatusOK, header, []byte(`<p>About</p>`)), nil
+		},
+		ConcurrencyStats: scraperapi.ConcurrencyStats{Limit: 1},
+	}
+
+	var fetched []string
+	for page, err := range crawl.New(mock).Crawl(context.Background(), "https://example.com/") {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		fetched = append(fetched, page.URL)
+	}
+
+	if !slices.Equal(fetched, []string{"https://example.com/", "https://example.com/about"}) {
+		t.Fatalf("unexpected pages %v", fetched)
+	}
+}
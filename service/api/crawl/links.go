@@ -0,0 +1,130 @@
+package crawl
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var errNotHTTP = errors.New("only http and https urls can be crawled")
+
+// Canonicalize returns the canonical form of an absolute http or https URL, used to dedupe the crawl frontier: the scheme and
+// host are lowercased, default ports and fragments are dropped, dot segments are resolved, an empty path becomes "/" and query
+// parameters are sorted.
+func Canonicalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+
+	u, err = canonicalize(u)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// canonicalize returns the canonical form of u. See Canonicalize.
+func canonicalize(u *url.URL) (*url.URL, error) {
+	// resolving the url against an empty base removes dot segments from its path
+	c := (&url.URL{}).ResolveReference(u)
+
+	c.Scheme = strings.ToLower(c.Scheme)
+	if c.Scheme != "http" && c.Scheme != "https" {
+		return nil, errNotHTTP
+	}
+	if c.Host == "" {
+		return nil, errors.New("url must be absolute")
+	}
+
+	host, port := c.Hostname(), c.Port()
+	if (c.Scheme == "http" && port == "80") || (c.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	c.Host = strings.ToLower(host)
+	if port != "" {
+		c.Host = net.JoinHostPort(c.Host, port)
+	} else if strings.Contains(c.Host, ":") {
+		c.Host = "[" + c.Host + "]"
+	}
+
+	if c.Path == "" {
+		c.Path = "/"
+		c.RawPath = ""
+	}
+	c.Fragment, c.RawFragment = "", ""
+	if c.RawQuery != "" {
+		c.RawQuery = c.Query().Encode()
+	}
+	c.ForceQuery = false
+
+	return c, nil
+}
+
+// extractLinks returns the canonical http and https links of an HTML document, resolved against base or the document's own
+// <base href>. Duplicates are removed, keeping the document order.
+func extractLinks(base *url.URL, body []byte) []*url.URL {
+	var (
+		links []*url.URL
+		seen  = make(map[string]struct{})
+		z     = html.NewTokenizer(bytes.NewReader(body))
+	)
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return links
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			href, ok := attr(token, "href")
+			if !ok {
+				continue
+			}
+
+			ref, err := url.Parse(strings.TrimSpace(href))
+			if err != nil {
+				continue
+			}
+
+			switch token.DataAtom {
+			case atom.Base:
+				base = base.ResolveReference(ref)
+			case atom.A, atom.Area:
+				link, err := canonicalize(base.ResolveReference(ref))
+				if err != nil {
+					continue
+				}
+				if _, dup := seen[link.String()]; !dup {
+					seen[link.String()] = struct{}{}
+					links = append(links, link)
+				}
+			}
+		}
+	}
+}
+
+// attr returns the value of the given attribute of token, if present.
+func attr(token html.Token, name string) (string, bool) {
+	for _, a := range token.Attr {
+		if a.Key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// isHTML reports whether a Content-Type header value is an HTML document.
+func isHTML(contentType string) bool {
+	return strings.Contains(contentType, "text/html") || strings.Contains(contentType, "application/xhtml+xml")
+}
+
+// domainMatches reports whether host is domain or one of its subdomains. A leading "www." on host is ignored.
+func domainMatches(host, domain string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
@@ -0,0 +1,105 @@
+package crawl
+
+import (
+	"net/url"
+	"strings"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+const (
+	defaultMaxDepth    = 2
+	defaultMaxPages    = 100
+	defaultConcurrency = 5
+)
+
+// ParamsFunc returns the request parameters to fetch target with. depth is the number of links followed from a seed to reach
+// target (0 for the seeds themselves). Returning nil fetches the page without parameters.
+type ParamsFunc func(target *url.URL, depth int) *scraperapi.RequestParameters
+
+// ScopeFunc reports whether a discovered link should be followed.
+type ScopeFunc func(target *url.URL) bool
+
+// Option configures the Crawler.
+type Option interface {
+	apply(*options)
+}
+
+// options holds the configuration for the Crawler
+type options struct {
+	// maxDepth is the maximum number of links followed from a seed. Defaults to 2.
+	maxDepth int
+	// maxPages is the maximum number of pages fetched in a crawl, seeds included. Defaults to 100.
+	maxPages int
+	// allowedDomains restricts the crawl to these domains and their subdomains. Defaults to the domains of the seeds.
+	allowedDomains []string
+	// scope, if set, replaces the domain scoping.
+	scope ScopeFunc
+	// params picks the request parameters of every page. Defaults to no parameters.
+	params ParamsFunc
+}
+
+// defaultOptions returns the default options for the Crawler.
+func defaultOptions() options {
+	return options{
+		maxDepth: defaultMaxDepth,
+		maxPages: defaultMaxPages,
+	}
+}
+
+// funcOption wraps a function that modifies options into an implementation of the Option interface.
+type funcOption struct {
+	f func(*options)
+}
+
+func (fo *funcOption) apply(o *options) {
+	fo.f(o)
+}
+
+func newFuncOption(f func(*options)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+// WithMaxDepth returns an Option which configures the maximum number of links followed from a seed. A depth of 0 only fetches the
+// seeds.
+func WithMaxDepth(maxDepth int) Option {
+	return newFuncOption(func(o *options) {
+		o.maxDepth = maxDepth
+	})
+}
+
+// WithMaxPages returns an Option which configures the maximum number of pages fetched in a crawl, seeds included. Every page is a
+// billed request, so keep this in line with your budget.
+func WithMaxPages(maxPages int) Option {
+	return newFuncOption(func(o *options) {
+		o.maxPages = maxPages
+	})
+}
+
+// WithAllowedDomains returns an Option which restricts the crawl to the given domains and their subdomains, instead of the domains
+// of the seeds.
+func WithAllowedDomains(domains ...string) Option {
+	return newFuncOption(func(o *options) {
+		for _, domain := range domains {
+			o.allowedDomains = append(o.allowedDomains, strings.ToLower(strings.TrimPrefix(domain, "www.")))
+		}
+	})
+}
+
+// WithScope returns an Option which decides which discovered links are followed, replacing the domain scoping. Links that are
+// not http or https are never followed.
+func WithScope(scope ScopeFunc) Option {
+	return newFuncOption(func(o *options) {
+		o.scope = scope
+	})
+}
+
+// WithParams returns an Option which picks the request parameters of every page, e.g. to only enable JSRender on the pages that
+// need it.
+func WithParams(params ParamsFunc) Option {
+	return newFuncOption(func(o *options) {
+		o.params = params
+	})
+}
@@ -0,0 +1,193 @@
+package scraperapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// cssExtractorTag is the struct tag DecodeCSSExtraction and CSSExtractorFor read selectors from, e.g. `zr:"h1.title"` or
+// `zr:"a.link @href"` to extract an attribute instead of the text content.
+const cssExtractorTag = "zr"
+
+// CSSExtractorBuilder builds the css_extractor parameter: a JSON object mapping each result key to a CSS selector, optionally
+// followed by " @attribute" to extract that attribute instead of the element text.
+//
+// See https://docs.zenrows.com/scraper-api/features/output#css-selectors for more information.
+type CSSExtractorBuilder struct {
+	selectors map[string]string
+}
+
+// CSSExtractor returns an empty CSSExtractorBuilder.
+//
+//	params := &scraperapi.RequestParameters{
+//		CSSExtractor: scraperapi.CSSExtractor().Text("title", "h1").Attr("links", "a.link", "href").String(),
+//	}
+func CSSExtractor() *CSSExtractorBuilder {
+	return &CSSExtractorBuilder{selectors: map[string]string{}}
+}
+
+// CSSExtractorFor returns a CSSExtractorBuilder with the selectors declared in the `zr` struct tags of T, which must be a struct
+// (or a pointer to one). Keys are taken from the json tag of each field, falling back to the field name, so the same T can be
+// passed to DecodeCSSExtraction to decode the result.
+func CSSExtractorFor[T any]() (*CSSExtractorBuilder, error) {
+	fields, err := cssExtractorFields(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	builder := CSSExtractor()
+	for _, field := range fields {
+		builder.selectors[field.key] = field.selector
+	}
+	return builder, nil
+}
+
+// Text extracts the text content of the elements matching selector under key.
+func (b *CSSExtractorBuilder) Text(key, selector string) *CSSExtractorBuilder {
+	b.selectors[key] = selector
+	return b
+}
+
+// Attr extracts the given attribute of the elements matching selector under key.
+func (b *CSSExtractorBuilder) Attr(key, selector, attribute string) *CSSExtractorBuilder {
+	b.selectors[key] = selector + " @" + attribute
+	return b
+}
+
+// Selectors returns a copy of the key to selector map built so far.
+func (b *CSSExtractorBuilder) Selectors() map[string]string {
+	selectors := make(map[string]string, len(b.selectors))
+	for k, v := range b.selectors {
+		selectors[k] = v
+	}
+	return selectors
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b *CSSExtractorBuilder) MarshalJSON() ([]byte, error) {
+	return json.Marshal(b.selectors)
+}
+
+// String returns the serialized selectors, ready to be assigned to RequestParameters.CSSExtractor.
+func (b *CSSExtractorBuilder) String() string {
+	data, err := b.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// DecodeCSSExtraction decodes the result of a request sent with a css_extractor built from T (see CSSExtractorFor) into a T.
+//
+// The API returns a single value when a selector matches one element and a list when it matches several, so the result is
+// normalized to the field type: a single value is wrapped for slice fields, and the first element of a list is kept for string
+// fields. It returns an UnexpectedContentError if the response does not carry a CSS extraction result, e.g. a problem+json body.
+func DecodeCSSExtraction[T any](resp *Response) (T, error) {
+	var result T
+
+	if resp.IsError() {
+		return result, resp.unexpectedContent("css extraction result")
+	}
+	if err := resp.streamedContent("css extraction result"); err != nil {
+		return result, err
+	}
+
+	fields, err := cssExtractorFields(reflect.TypeFor[T]())
+	if err != nil {
+		return result, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(resp.Body(), &raw); err != nil {
+		return result, UnexpectedContentError{Expected: "css extraction result", ContentType: resp.Header().Get("Content-Type"), Err: err}
+	}
+
+	target := reflect.ValueOf(&result).Elem()
+	if target.Kind() == reflect.Pointer {
+		target.Set(reflect.New(target.Type().Elem()))
+		target = target.Elem()
+	}
+
+	for _, field := range fields {
+		value, ok := raw[field.key]
+		if !ok {
+			continue
+		}
+		if err := decodeCSSValue(value, target.FieldByIndex(field.index)); err != nil {
+			return result, fmt.Errorf("css extraction: decode %q: %w", field.key, err)
+		}
+	}
+
+	return result, nil
+}
+
+// cssExtractorField is a struct field tagged with a CSS selector.
+type cssExtractorField struct {
+	index    []int
+	key      string
+	selector string
+}
+
+// cssExtractorFields returns the fields of the given struct type tagged with a CSS selector.
+func cssExtractorFields(t reflect.Type) ([]cssExtractorField, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("css extraction: %s is not a struct", t)
+	}
+
+	var fields []cssExtractorField
+	for _, field := range reflect.VisibleFields(t) {
+		selector := strings.TrimSpace(field.Tag.Get(cssExtractorTag))
+		if selector == "" || !field.IsExported() {
+			continue
+		}
+
+		key := field.Name
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+			key = name
+		}
+		fields = append(fields, cssExtractorField{index: field.Index, key: key, selector: selector})
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("css extraction: %s has no fields tagged with %q", t, cssExtractorTag)
+	}
+	return fields, nil
+}
+
+// decodeCSSValue decodes a single extracted value into dst, normalizing single values and lists to the type of dst.
+func decodeCSSValue(value json.RawMessage, dst reflect.Value) error {
+	trimmed := strings.TrimSpace(string(value))
+	if trimmed == "null" {
+		// no match: leave dst empty
+		return nil
+	}
+	isList := strings.HasPrefix(trimmed, "[")
+
+	switch {
+	case dst.Kind() == reflect.Slice && !isList:
+		// a single match: decode it as the only element of the list
+		elem := reflect.New(dst.Type().Elem())
+		if err := json.Unmarshal(value, elem.Interface()); err != nil {
+			return err
+		}
+		dst.Set(reflect.Append(reflect.MakeSlice(dst.Type(), 0, 1), elem.Elem()))
+		return nil
+	case dst.Kind() != reflect.Slice && isList:
+		// several matches: keep the first one
+		var items []json.RawMessage
+		if err := json.Unmarshal(value, &items); err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return json.Unmarshal(items[0], dst.Addr().Interface())
+	default:
+		return json.Unmarshal(value, dst.Addr().Interface())
+	}
+}
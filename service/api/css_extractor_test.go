@@ -0,0 +1,116 @@
+package scraperapi_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+type productPage struct {
+	Title  string   `json:"title" zr:"h1.title"`
+	Price  string   `json:"price" zr:".price"`
+	Links  []string `json:"links" zr:"a.link @href"`
+	Tags   []string `zr:".tag"`
+	Ignore string
+}
+
+func TestCSSExtractorBuilderSerializesSelectors(t *testing.T) {
+	got := scraperapi.CSSExtractor().Text("title", "h1").Attr("links", "a.link", "href").String()
+	want := `{"links":"a.link @href","title":"h1"}`
+	if got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestCSSExtractorForReadsStructTags(t *testing.T) {
+	builder, err := scraperapi.CSSExtractorFor[productPage]()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	selectors := builder.Selectors()
+	want := map[string]string{"title": "h1.title", "price": ".price", "links": "a.link @href", "Tags": ".tag"}
+	if len(selectors) != len(want) {
+		t.Fatalf("got %v, want %v", selectors, want)
+	}
+	for key, selector := range want {
+		if selectors[key] != selector {
+			t.Fatalf("%s: got %q, want %q", key, selectors[key], selector)
+		}
+	}
+}
+
+func TestCSSExtractorForRejectsUntaggedTypes(t *testing.T) {
+	if _, err := scraperapi.CSSExtractorFor[struct{ Name string }](); err == nil {
+		t.Fatal("expected an error for a struct without zr tags")
+	}
+	if _, err := scraperapi.CSSExtractorFor[string](); err == nil {
+		t.Fatal("expected an error for a non-struct type")
+	}
+}
+
+func TestDecodeCSSExtractionNormalizesSingleValuesAndLists(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"title":["Main title","Secondary title"],"price":"$10","links":"/only","Tags":["a","b"]}`))
+	})
+
+	page, err := scraperapi.DecodeCSSExtraction[productPage](res)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Title != "Main title" {
+		t.Fatalf("expected the first match for a string field, got %q", page.Title)
+	}
+	if page.Price != "$10" {
+		t.Fatalf("unexpected price: %q", page.Price)
+	}
+	if len(page.Links) != 1 || page.Links[0] != "/only" {
+		t.Fatalf("expected a single match to be wrapped for a slice field, got %v", page.Links)
+	}
+	if len(page.Tags) != 2 {
+		t.Fatalf("unexpected tags: %v", page.Tags)
+	}
+}
+
+func TestDecodeCSSExtractionLeavesFieldsWithoutMatchesEmpty(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"title":null,"links":null,"Tags": null }`))
+	})
+
+	page, err := scraperapi.DecodeCSSExtraction[productPage](res)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.Title != "" || page.Links != nil || page.Tags != nil {
+		t.Fatalf("expected fields without matches to be left empty, got %+v", page)
+	}
+}
+
+func TestDecodeCSSExtractionIntoPointer(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(`{"title":"Hello"}`)) })
+
+	page, err := scraperapi.DecodeCSSExtraction[*productPage](res)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page == nil || page.Title != "Hello" {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+}
+
+func TestDecodeCSSExtractionRejectsErrorResponses(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"title":"Could not get content","status":422}`))
+	})
+
+	var unexpected scraperapi.UnexpectedContentError
+	if _, err := scraperapi.DecodeCSSExtraction[productPage](res); !errors.As(err, &unexpected) {
+		t.Fatalf("expected UnexpectedContentError, got %v", err)
+	}
+}
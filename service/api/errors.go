@@ -1,8 +1,13 @@
 package scraperapi
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
 )
 
 // NotConfiguredError results when the ZenRows Fetch API client is used without a valid API Key.
@@ -54,3 +59,142 @@ func (e InvalidParameterError) Error() string {
 
 	return e.Msg
 }
+
+// InvalidJSInstructionError results when RequestParameters.JSInstructions cannot be parsed, or one of its instructions is unknown or
+// has a malformed value. Index is the position of the offending instruction, or -1 when the script itself is not a JSON array.
+type InvalidJSInstructionError struct {
+	Index  int
+	Action JSInstructionAction
+	Msg    string
+}
+
+func (e InvalidJSInstructionError) Error() string {
+	if e.Index < 0 {
+		return "invalid js_instructions: " + e.Msg
+	}
+
+	return fmt.Sprintf("invalid js_instructions[%d]: %s", e.Index, e.Msg)
+}
+
+// UnexpectedContentError results when a Response helper expects a specific kind of content (e.g. a json_response envelope or a
+// screenshot), but the response carries something else.
+type UnexpectedContentError struct {
+	Expected    string
+	ContentType string
+	Err         error
+}
+
+func (e UnexpectedContentError) Unwrap() error {
+	return e.Err
+}
+
+func (e UnexpectedContentError) Error() string {
+	msg := "unexpected response content: expected " + e.Expected
+	if e.ContentType != "" {
+		msg += ", got " + e.ContentType
+	}
+
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+
+	return msg
+}
+
+// BodyTooLargeError results when a streamed response body is larger than the cap configured with WithMaxStreamBodySize.
+type BodyTooLargeError struct {
+	Limit int64
+}
+
+func (e BodyTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds the %d bytes limit", e.Limit)
+}
+
+// StreamedBodyError results when a Response helper that decodes the body (e.g. Response.JSON or Response.PDF) is used on a
+// response returned by Client.ScrapeStream, whose body is not buffered: read it from Response.BodyReader instead.
+type StreamedBodyError struct{}
+
+func (StreamedBodyError) Error() string {
+	return "response body is streamed; read it from Response.BodyReader"
+}
+
+// DeadlineUnreachableError results when a request is dropped before it is sent, because its context deadline is closer than the
+// time the fastest request took to get a response so far. It wraps context.DeadlineExceeded.
+type DeadlineUnreachableError struct {
+	Remaining time.Duration
+	Expected  time.Duration
+}
+
+func (e DeadlineUnreachableError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+func (e DeadlineUnreachableError) Error() string {
+	return fmt.Sprintf("request dropped: %s left before the deadline, but requests take at least %s", e.Remaining, e.Expected)
+}
+
+// BudgetExceededError results when a request is rejected because its worst-case cost would exceed the budget of the client (see
+// WithBudget) or of the context (see ContextWithBudget).
+type BudgetExceededError struct {
+	// Scope is "client" or "context", depending on the budget that would be exceeded.
+	Scope    string
+	Limit    float64
+	Spent    float64
+	Reserved float64
+	// Required is the worst-case cost of the rejected request.
+	Required float64
+}
+
+func (e BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s budget exceeded: request may cost %g credits, but only %g of %g are left",
+		e.Scope, e.Required, e.Limit-e.Spent-e.Reserved, e.Limit)
+}
+
+// maxHTTPErrorBodySnippet caps how much of the response body an HTTPError keeps.
+const maxHTTPErrorBodySnippet = 512
+
+// HTTPError results when the ZenRows Fetch API responds with an error status code (see Response.Error). Problem is the problem
+// description of the response, if it has one; otherwise, e.g. for an HTML error page from an edge proxy, Body tells what went wrong.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	// Body is the start of the response body, up to 512 bytes.
+	Body    string
+	Problem *problem.Problem
+}
+
+func (e HTTPError) Unwrap() error {
+	if e.Problem == nil {
+		return nil
+	}
+
+	return e.Problem
+}
+
+func (e HTTPError) Error() string {
+	if e.Problem != nil {
+		return e.Problem.Error()
+	}
+
+	msg := "unexpected response status: " + e.Status
+	if e.Status == "" {
+		msg = fmt.Sprintf("unexpected response status: %d", e.StatusCode)
+	}
+	if e.Body != "" {
+		return msg + ": " + e.Body
+	}
+
+	return msg
+}
+
+// bodySnippet returns the start of a response body, up to maxHTTPErrorBodySnippet bytes, without cutting a UTF-8 sequence.
+func bodySnippet(body []byte) string {
+	if len(body) > maxHTTPErrorBodySnippet {
+		body = body[:maxHTTPErrorBodySnippet]
+		for i := 0; i < utf8.UTFMax && !utf8.Valid(body); i++ {
+			body = body[:len(body)-1]
+		}
+	}
+
+	return strings.TrimSpace(string(body))
+}
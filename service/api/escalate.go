@@ -0,0 +1,280 @@
+package scraperapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
+)
+
+// defaultEscalationTTL is how long the client remembers the cheapest configuration that worked for a domain, unless set with
+// WithEscalationStore.
+const defaultEscalationTTL = 24 * time.Hour
+
+// DefaultEscalationLadder returns the ladder of request parameters Client.Escalate tries by default, from the cheapest to the most
+// expensive: base, base with JSRender, base with UsePremiumProxies, and base with both. If proxyCountry is set, it is used by the
+// steps with premium proxies, the only ones that support it; the ProxyCountry of base is ignored.
+func DefaultEscalationLadder(base RequestParameters, proxyCountry string) []RequestParameters {
+	base.ProxyCountry = ""
+
+	js := base
+	js.JSRender = true
+
+	premium := base
+	premium.UsePremiumProxies = true
+	premium.ProxyCountry = proxyCountry
+
+	both := premium
+	both.JSRender = true
+
+	return []RequestParameters{base, js, premium, both}
+}
+
+// WithEscalationLadder returns an Option which configures the ladder of request parameters Client.Escalate tries, from the cheapest
+// to the most expensive. Defaults to DefaultEscalationLadder(RequestParameters{}, "").
+func WithEscalationLadder(ladder ...RequestParameters) Option {
+	return newFuncDialOption(func(o *options) {
+		o.escalationLadder = ladder
+	})
+}
+
+// WithEscalationStore returns an Option which configures where Client.Escalate remembers the cheapest configuration that worked
+// for each domain, and for how long. Defaults to an in-memory store private to the client, remembering configurations for 24 hours.
+func WithEscalationStore(store EscalationStore, ttl time.Duration) Option {
+	return newFuncDialOption(func(o *options) {
+		o.escalationStore = store
+		o.escalationTTL = ttl
+	})
+}
+
+// EscalationMemory is the cheapest configuration of the escalation ladder that worked for a domain.
+type EscalationMemory struct {
+	// Step is the index of the configuration in the escalation ladder.
+	Step int `json:"step"`
+	// Tier is the pricing tier of the configuration, to tell when the ladder changed since it was remembered.
+	Tier Tier `json:"tier"`
+	// ExpiresAt is when the configuration is forgotten, and the ladder is climbed from the bottom again.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// EscalationStore remembers the cheapest configuration of the escalation ladder that worked for each domain. Implementations must be
+// safe for concurrent use.
+type EscalationStore interface {
+	// Get returns what is remembered for the domain, if anything. Expired memories are ignored by the client.
+	Get(domain string) (EscalationMemory, bool)
+	// Set remembers the configuration that worked for the domain.
+	Set(domain string, memory EscalationMemory)
+	// Delete forgets what is remembered for the domain.
+	Delete(domain string)
+}
+
+// MemoryEscalationStore is an in-memory EscalationStore. It can be persisted with WriteTo and restored with ReadFrom, e.g. to keep
+// what was learned between runs. It is safe for concurrent use.
+type MemoryEscalationStore struct {
+	mu       sync.Mutex
+	memories map[string]EscalationMemory
+}
+
+// NewMemoryEscalationStore creates and returns a new, empty MemoryEscalationStore.
+func NewMemoryEscalationStore() *MemoryEscalationStore {
+	return &MemoryEscalationStore{memories: make(map[string]EscalationMemory)}
+}
+
+// Get implements EscalationStore.
+func (s *MemoryEscalationStore) Get(domain string) (EscalationMemory, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	memory, ok := s.memories[domain]
+	return memory, ok
+}
+
+// Set implements EscalationStore.
+func (s *MemoryEscalationStore) Set(domain string, memory EscalationMemory) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.memories[domain] = memory
+}
+
+// Delete implements EscalationStore.
+func (s *MemoryEscalationStore) Delete(domain string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.memories, domain)
+}
+
+// WriteTo writes what the store remembers to w, as a JSON object keyed by domain. Expired memories are left out.
+func (s *MemoryEscalationStore) WriteTo(w io.Writer) (int64, error) {
+	s.mu.Lock()
+	now := time.Now()
+	memories := make(map[string]EscalationMemory, len(s.memories))
+	for domain, memory := range s.memories {
+		if now.Before(memory.ExpiresAt) {
+			memories[domain] = memory
+		}
+	}
+	s.mu.Unlock()
+
+	data, err := json.Marshal(memories)
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom restores what was written by WriteTo from r, on top of what the store already remembers.
+func (s *MemoryEscalationStore) ReadFrom(r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return int64(len(data)), err
+	}
+
+	var memories map[string]EscalationMemory
+	if err = json.Unmarshal(data, &memories); err != nil {
+		return int64(len(data)), err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for domain, memory := range memories {
+		s.memories[domain] = memory
+	}
+	return int64(len(data)), nil
+}
+
+// EscalationAttempt is one request sent by Client.Escalate.
+type EscalationAttempt struct {
+	// Step is the index of the configuration in the escalation ladder.
+	Step int
+	// Params are the request parameters the request was sent with.
+	Params RequestParameters
+	// StatusCode is the status code of the response, or 0 if the request failed.
+	StatusCode int
+	// Cost is what the request was charged.
+	Cost Cost
+}
+
+// EscalationResult is the outcome of Client.Escalate.
+type EscalationResult struct {
+	// Response is the response of the last attempt: the first successful one, the failure that stopped the climb, or the last
+	// blocked one if the target blocked every configuration.
+	Response *Response
+	// Step is the index in the escalation ladder of the configuration that produced Response.
+	Step int
+	// Remembered is true if the ladder was climbed from the configuration remembered for the domain, instead of from the bottom.
+	Remembered bool
+	// Attempts are the requests sent, in order.
+	Attempts []EscalationAttempt
+}
+
+// Credits returns the credits charged for all the attempts.
+func (r *EscalationResult) Credits() float64 {
+	var credits float64
+	for _, attempt := range r.Attempts {
+		credits += attempt.Cost.Credits
+	}
+	return credits
+}
+
+// Escalate scrapes the given target URL with an HTTP GET request, climbing the escalation ladder of the client (see
+// WithEscalationLadder) until a configuration succeeds. It is an explicit, client-side alternative to ModeAuto.
+//
+// The climb only goes on while the target blocks the requests: a problem of category problem.CategoryTargetBlocked, e.g. REQS002
+// for a target requiring JS rendering or premium proxies, or a 403 or 422 response without a problem. Any other failure, e.g. an invalid API key, an exhausted plan, invalid parameters or a rate
+// limit, stops the climb, and its HTTPError is returned along with the result.
+//
+// The cheapest configuration that worked is remembered for the domain of the target URL (see WithEscalationStore), so the next
+// requests to the domain start from it. If the target blocks every configuration, the domain is forgotten, and the result holds
+// the last blocked response; errors sending a request stop the climb and are returned along with the attempts made so far.
+func (c *Client) Escalate(ctx context.Context, targetURL string) (*EscalationResult, error) {
+	ladder := c.escalationLadder()
+	store := c.escalationStore()
+	domain := escalationDomain(targetURL)
+
+	result := &EscalationResult{}
+	if memory, ok := store.Get(domain); ok && time.Now().Before(memory.ExpiresAt) &&
+		memory.Step >= 0 && memory.Step < len(ladder) {
+		if tier, _ := ladder[memory.Step].tier(); tier == memory.Tier {
+			result.Step, result.Remembered = memory.Step, true
+		}
+	}
+
+	for step := result.Step; step < len(ladder); step++ {
+		params := ladder[step]
+		response, err := c.Get(ctx, targetURL, &params)
+		if err != nil {
+			return result, err
+		}
+
+		result.Response, result.Step = response, step
+		result.Attempts = append(result.Attempts, EscalationAttempt{
+			Step:       step,
+			Params:     params,
+			StatusCode: response.StatusCode(),
+			Cost:       response.Cost(),
+		})
+
+		if response.IsSuccess() {
+			tier, _ := params.tier()
+			store.Set(domain, EscalationMemory{Step: step, Tier: tier, ExpiresAt: time.Now().Add(c.escalationTTL())})
+			return result, nil
+		}
+		if !targetBlocked(response) {
+			return result, response.Error()
+		}
+	}
+
+	store.Delete(domain)
+	return result, nil
+}
+
+// targetBlocked reports whether the target blocked the request of a failed response, the only failure worth a more expensive
+// configuration.
+func targetBlocked(response *Response) bool {
+	if prob := response.Problem(); prob != nil {
+		return prob.Category() == problem.CategoryTargetBlocked
+	}
+	return response.StatusCode() == http.StatusForbidden || response.StatusCode() == http.StatusUnprocessableEntity
+}
+
+// escalationLadder returns the escalation ladder of the client.
+func (c *Client) escalationLadder() []RequestParameters {
+	if len(c.cfg.escalationLadder) > 0 {
+		return c.cfg.escalationLadder
+	}
+	return DefaultEscalationLadder(RequestParameters{}, "")
+}
+
+// escalationStore returns the escalation store of the client.
+func (c *Client) escalationStore() EscalationStore {
+	if c.cfg.escalationStore != nil {
+		return c.cfg.escalationStore
+	}
+	return c.escalation
+}
+
+// escalationTTL returns how long the client remembers the configuration that worked for a domain.
+func (c *Client) escalationTTL() time.Duration {
+	if c.cfg.escalationStore != nil && c.cfg.escalationTTL > 0 {
+		return c.cfg.escalationTTL
+	}
+	return defaultEscalationTTL
+}
+
+// escalationDomain returns the domain the escalation ladder is remembered for: the lowercased host name of the target URL.
+func escalationDomain(targetURL string) string {
+	parsedURL, err := url.Parse(targetURL)
+	if err != nil || parsedURL.Hostname() == "" {
+		return strings.ToLower(targetURL)
+	}
+	return strings.ToLower(parsedURL.Hostname())
+}
@@ -0,0 +1,228 @@
+package scraperapi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
+)
+
+// escalationServer only succeeds for requests with premium proxies, charging the credits of their tier.
+func escalationServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		query := r.URL.Query()
+		if query.Get("premium_proxy") != "true" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if query.Get("js_render") == "true" {
+			w.Header().Set("X-Request-Cost", "25")
+		} else {
+			w.Header().Set("X-Request-Cost", "10")
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEscalateClimbsTheLadderAndRemembersTheDomain(t *testing.T) {
+	var hits atomic.Int32
+	server := escalationServer(t, &hits)
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"))
+
+	result, err := client.Escalate(context.Background(), "https://example.com/a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Response.IsSuccess() || result.Step != 2 || result.Remembered {
+		t.Fatalf("expected the premium proxy step to succeed, got step %d (status %d)", result.Step, result.Response.StatusCode())
+	}
+	if len(result.Attempts) != 3 || result.Credits() != 10 {
+		t.Fatalf("expected 3 attempts charged 10 credits, got %d attempts charged %g", len(result.Attempts), result.Credits())
+	}
+
+	hits.Store(0)
+	result, err = client.Escalate(context.Background(), "https://EXAMPLE.com/b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Remembered || result.Step != 2 || hits.Load() != 1 {
+		t.Fatalf("expected the remembered step to be tried first, got step %d after %d attempts", result.Step, hits.Load())
+	}
+}
+
+func TestEscalateForgetsExpiredMemories(t *testing.T) {
+	var hits atomic.Int32
+	server := escalationServer(t, &hits)
+	store := scraperapi.NewMemoryEscalationStore()
+	store.Set("example.com", scraperapi.EscalationMemory{Step: 3, Tier: scraperapi.TierJSAndPremium, ExpiresAt: time.Now().Add(-time.Minute)})
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithEscalationStore(store, time.Hour),
+	)
+
+	result, err := client.Escalate(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Remembered || result.Step != 2 {
+		t.Fatalf("expected the expired memory to be ignored, got step %d", result.Step)
+	}
+	if memory, ok := store.Get("example.com"); !ok || memory.Step != 2 || time.Until(memory.ExpiresAt) <= 50*time.Minute {
+		t.Fatalf("expected the cheaper step to be remembered for an hour, got %+v", memory)
+	}
+}
+
+func TestEscalateReturnsTheLastFailureWhenEveryStepFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	store := scraperapi.NewMemoryEscalationStore()
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithEscalationLadder(scraperapi.DefaultEscalationLadder(scraperapi.RequestParameters{}, "us")...),
+		scraperapi.WithEscalationStore(store, time.Hour),
+	)
+
+	result, err := client.Escalate(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Response.StatusCode() != http.StatusForbidden || len(result.Attempts) != 4 {
+		t.Fatalf("expected 4 failed attempts, got %d (status %d)", len(result.Attempts), result.Response.StatusCode())
+	}
+	if country := result.Attempts[3].Params.ProxyCountry; country != "us" {
+		t.Fatalf("expected the premium steps to use the proxy country, got %q", country)
+	}
+	if _, ok := store.Get("example.com"); ok {
+		t.Fatalf("expected nothing to be remembered")
+	}
+}
+
+func TestEscalateClimbsWhenTheTargetRequiresEscalation(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Query().Get("js_render") != "true" {
+			w.Header().Set("Content-Type", problem.ContentTypeJSON)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"REQS002","status":400}`))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithMaxRetryCount(0))
+	result, err := client.Escalate(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Step != 1 || !result.Response.IsSuccess() || hits.Load() != 2 {
+		t.Fatalf("expected the JS rendering step to succeed after REQS002, got step %d after %d attempts", result.Step, hits.Load())
+	}
+}
+
+func TestEscalateStopsOnFailuresTheTargetDidNotCause(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		code   problem.Code
+	}{
+		{"invalid API key", http.StatusUnauthorized, problem.CodeInvalidAPIKey},
+		{"no credit left", http.StatusPaymentRequired, problem.CodeNoCreditAvailable},
+		{"invalid parameters", http.StatusBadRequest, problem.CodeInvalidParameters},
+		{"rate limited", http.StatusTooManyRequests, problem.CodeConcurrencyExceeded},
+		{"not found", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hits atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hits.Add(1)
+				if tt.code != "" {
+					w.Header().Set("Content-Type", problem.ContentTypeJSON)
+					w.WriteHeader(tt.status)
+					_, _ = w.Write([]byte(`{"code":"` + string(tt.code) + `","status":` + strconv.Itoa(tt.status) + `}`))
+					return
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			store := scraperapi.NewMemoryEscalationStore()
+			store.Set("example.com", scraperapi.EscalationMemory{Step: 1, Tier: scraperapi.TierJS, ExpiresAt: time.Now().Add(time.Hour)})
+			client := scraperapi.NewClient(
+				scraperapi.WithBaseURL(server.URL),
+				scraperapi.WithAPIKey("k"),
+				scraperapi.WithMaxRetryCount(0),
+				scraperapi.WithEscalationStore(store, time.Hour),
+			)
+
+			result, err := client.Escalate(context.Background(), "https://example.com")
+			var httpErr scraperapi.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode != tt.status {
+				t.Fatalf("expected an HTTPError with status %d, got %v", tt.status, err)
+			}
+			if hits.Load() != 1 || len(result.Attempts) != 1 || result.Response.StatusCode() != tt.status {
+				t.Fatalf("expected the climb to stop after 1 attempt, got %d", hits.Load())
+			}
+			if memory, ok := store.Get("example.com"); !ok || memory.Step != 1 {
+				t.Fatalf("expected the remembered step to be kept, got %+v", memory)
+			}
+		})
+	}
+}
+
+func TestDefaultEscalationLadderOnlySetsTheProxyCountryOfPremiumSteps(t *testing.T) {
+	ladder := scraperapi.DefaultEscalationLadder(scraperapi.RequestParameters{ProxyCountry: "es"}, "us")
+	for step, params := range ladder {
+		want := ""
+		if params.UsePremiumProxies {
+			want = "us"
+		}
+		if params.ProxyCountry != want {
+			t.Fatalf("expected step %d to use proxy country %q, got %q", step, want, params.ProxyCountry)
+		}
+		if err := params.Validate(); err != nil {
+			t.Fatalf("expected step %d to be valid, got %v", step, err)
+		}
+	}
+}
+
+func TestMemoryEscalationStoreRoundTrip(t *testing.T) {
+	store := scraperapi.NewMemoryEscalationStore()
+	store.Set("fresh.com", scraperapi.EscalationMemory{Step: 1, Tier: scraperapi.TierJS, ExpiresAt: time.Now().Add(time.Hour)})
+	store.Set("stale.com", scraperapi.EscalationMemory{Step: 1, Tier: scraperapi.TierJS, ExpiresAt: time.Now().Add(-time.Hour)})
+
+	var buf bytes.Buffer
+	if _, err := store.WriteTo(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	restored := scraperapi.NewMemoryEscalationStore()
+	if _, err := restored.ReadFrom(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if memory, ok := restored.Get("fresh.com"); !ok || memory.Step != 1 || memory.Tier != scraperapi.TierJS {
+		t.Fatalf("expected fresh.com to be restored, got %+v", memory)
+	}
+	if _, ok := restored.Get("stale.com"); ok {
+		t.Fatalf("expected expired memories not to be persisted")
+	}
+}
@@ -0,0 +1,94 @@
+package scraperapi
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ExtractSource tells which request produced the result of Client.Extract.
+type ExtractSource string
+
+const (
+	// ExtractSourceExtract means the data comes from Extract itself, in the shape of the requested ExtractMode.
+	ExtractSourceExtract ExtractSource = "extract"
+	// ExtractSourceAutoParse means ExtractModeAuto hit a domain not yet enabled for the Extract beta (AUTH010), and the data
+	// comes from the AutoParse fallback instead, in the AutoParse shape.
+	ExtractSourceAutoParse ExtractSource = "autoparse"
+)
+
+// ExtractAutoResult is the result of ExtractModeAuto: the fields Extract inferred for the page, keyed by field name.
+type ExtractAutoResult map[string]any
+
+// ExtractNativeResult is the result of ExtractModeNative: the structured data the page itself declares, keyed by field name.
+type ExtractNativeResult map[string]any
+
+// ExtractStandardResult is the result of ExtractModeStandard: the fields of the standard extraction contract, keyed by field name.
+type ExtractStandardResult map[string]any
+
+// ExtractResult is the typed result of ExtractAs. Exactly one of Data and AutoParse is set, depending on Source.
+type ExtractResult[T any] struct {
+	// Source tells whether the data came from Extract or from the AUTH010 AutoParse fallback.
+	Source ExtractSource
+	// Mode is the ExtractMode that was requested.
+	Mode ExtractMode
+	// Data is the decoded Extract result. Only set when Source is ExtractSourceExtract.
+	Data T
+	// AutoParse is the raw AutoParse result, whose shape depends on the page. Only set when Source is ExtractSourceAutoParse; decode
+	// it with DecodeAutoParse.
+	AutoParse json.RawMessage
+	// Response is the response the result was decoded from.
+	Response *Response
+}
+
+// FromFallback reports whether the result comes from the AutoParse fallback rather than from Extract.
+func (r *ExtractResult[T]) FromFallback() bool {
+	return r.Source == ExtractSourceAutoParse
+}
+
+// DecodeAutoParse unmarshals the AutoParse fallback result into v.
+func (r *ExtractResult[T]) DecodeAutoParse(v any) error {
+	return json.Unmarshal(r.AutoParse, v)
+}
+
+// ExtractSource method returns which request produced a response returned by Client.Extract, or an empty string for responses
+// that do not come from Client.Extract.
+func (r *Response) ExtractSource() ExtractSource {
+	return r.extractSource
+}
+
+// ExtractAs runs client.Extract (see Client.Extract) and decodes the result into T, typically one of ExtractAutoResult,
+// ExtractNativeResult and ExtractStandardResult (matching params.Extract), or a struct of your own. client is usually a *Client, or
+// a fake Extractor in tests. When Extract falls back to AutoParse, Data is left zero and the AutoParse result is returned in
+// AutoParse instead, so callers can branch on Source without sniffing the JSON.
+//
+// It returns an UnexpectedContentError when the response is not a JSON result, e.g. a problem+json body; the error wraps the
+// response problem.
+func ExtractAs[T any](ctx context.Context, client Extractor, targetURL string, params *RequestParameters) (*ExtractResult[T], error) {
+	response, err := client.Extract(ctx, targetURL, params)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := ExtractModeAuto
+	if params != nil && params.Extract != "" {
+		mode = params.Extract
+	}
+	result := &ExtractResult[T]{Source: response.ExtractSource(), Mode: mode, Response: response}
+
+	if response.IsError() {
+		return result, response.unexpectedContent("extract result")
+	}
+
+	if result.Source == ExtractSourceAutoParse {
+		if !json.Valid(response.Body()) {
+			return result, response.unexpectedContent("autoparse result")
+		}
+		result.AutoParse = json.RawMessage(response.Body())
+		return result, nil
+	}
+
+	if err := json.Unmarshal(response.Body(), &result.Data); err != nil {
+		return result, UnexpectedContentError{Expected: "extract result", ContentType: response.Header().Get("Content-Type"), Err: err}
+	}
+	return result, nil
+}
@@ -0,0 +1,127 @@
+package scraperapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
+)
+
+func TestExtractAsDecodesExtractResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"title":"Widget","price":9.99}`))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("test-key"))
+
+	result, err := scraperapi.ExtractAs[scraperapi.ExtractAutoResult](context.Background(), client, "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Source != scraperapi.ExtractSourceExtract || result.FromFallback() {
+		t.Fatalf("expected the result to come from Extract, got %q", result.Source)
+	}
+	if result.Mode != scraperapi.ExtractModeAuto {
+		t.Fatalf("expected the auto mode to be reported, got %q", result.Mode)
+	}
+	if result.Data["title"] != "Widget" {
+		t.Fatalf("unexpected data: %v", result.Data)
+	}
+}
+
+func TestExtractAsReportsAutoParseFallback(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"code":"AUTH010","title":"Domain not enabled","status":402}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"name":"Widget"}]`))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("test-key"))
+
+	type product struct {
+		Title string `json:"title"`
+	}
+	result, err := scraperapi.ExtractAs[product](context.Background(), client, "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.FromFallback() || result.Response.ExtractSource() != scraperapi.ExtractSourceAutoParse {
+		t.Fatalf("expected the result to come from the AutoParse fallback, got %q", result.Source)
+	}
+	if result.Data.Title != "" {
+		t.Fatalf("expected Data to be left zero on fallback, got %+v", result.Data)
+	}
+
+	var items []map[string]string
+	if err := result.DecodeAutoParse(&items); err != nil || len(items) != 1 || items[0]["name"] != "Widget" {
+		t.Fatalf("unexpected autoparse result: %v (%v)", items, err)
+	}
+}
+
+func TestExtractAsWrapsProblemOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"code":"AUTH004","title":"No credit available","status":402}`))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("test-key"))
+
+	params := &scraperapi.RequestParameters{Extract: scraperapi.ExtractModeNative}
+	result, err := scraperapi.ExtractAs[scraperapi.ExtractNativeResult](context.Background(), client, "https://example.com", params)
+	var prob *problem.Problem
+	if !errors.As(err, &prob) || prob.Code != "AUTH004" {
+		t.Fatalf("expected the error to wrap the AUTH004 problem, got %v", err)
+	}
+	if result == nil || result.Mode != scraperapi.ExtractModeNative || result.Response == nil {
+		t.Fatalf("expected the result to still carry the mode and response, got %+v", result)
+	}
+}
+
+func TestResponseExtractSourceIsEmptyOutsideExtract(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	if res.ExtractSource() != "" {
+		t.Fatalf("expected no extract source for a plain Get, got %q", res.ExtractSource())
+	}
+}
+
+// extractorFunc adapts a function to scraperapi.Extractor.
+type extractorFunc func(ctx context.Context, targetURL string, params *scraperapi.RequestParameters) (*scraperapi.Response, error)
+
+func (f extractorFunc) Extract(ctx context.Context, targetURL string, params *scraperapi.RequestParameters) (*scraperapi.Response, error) {
+	return f(ctx, targetURL, params)
+}
+
+func TestExtractAsAcceptsFakeExtractor(t *testing.T) {
+	fake := extractorFunc(func(_ context.Context, targetURL string, _ *scraperapi.RequestParameters) (*scraperapi.Response, error) {
+		if targetURL != "https://example.com/product/1" {
+			t.Errorf("unexpected target url %q", targetURL)
+		}
+		header := http.Header{"Content-Type": []string{"application/json"}}
+		return scraperapi.NewResponse(http.StatusOK, header, []byte(`{"@type":"Product","name":"Widget"}`)), nil
+	})
+
+	params := &scraperapi.RequestParameters{Extract: scraperapi.ExtractModeNative}
+	result, err := scraperapi.ExtractAs[scraperapi.ExtractNativeResult](context.Background(), fake, "https://example.com/product/1", params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Mode != scraperapi.ExtractModeNative || result.Data["name"] != "Widget" {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+}
@@ -7,6 +7,5 @@ require (
 	github.com/go-resty/resty/v2 v2.15.3
 	github.com/gorilla/schema v1.4.1
 	github.com/hashicorp/go-version v1.7.0
+	golang.org/x/net v0.55.0
 )
-
-require golang.org/x/net v0.55.0 // indirect
@@ -2,20 +2,38 @@ package scraperapi
 
 import "context"
 
-//go:generate mockery
+// IClient is the interface of the methods of Client that send requests, e.g. to inject a fake client in code under test: Client
+// implements it, and scraperapitest.MockClient is a ready-made fake.
 type IClient interface {
 	// Scrape sends a request to the ZenRows Fetch API to scrape the given target URL using the specified method and parameters.
-	Scrape(ctx context.Context, targetURL, method string, params RequestParameters) (*Response, error)
+	Scrape(ctx context.Context, method, targetURL string, params *RequestParameters, body any) (*Response, error)
+	// ScrapeStream works like Scrape, but does not buffer the response body. Close the response when done.
+	ScrapeStream(ctx context.Context, method, targetURL string, params *RequestParameters, body any) (*Response, error)
 	// Get sends a GET request to the ZenRows Fetch API to scrape the given target URL using the specified parameters.
-	Get(ctx context.Context, targetURL string, params RequestParameters) (*Response, error)
+	Get(ctx context.Context, targetURL string, params *RequestParameters) (*Response, error)
+	// GetStream sends a GET request like Get, streaming the response body. Close the response when done.
+	GetStream(ctx context.Context, targetURL string, params *RequestParameters) (*Response, error)
 	// Fetch sends a GET request to scrape the given target URL — an alias for Get, named to match
 	// ZenRows' "Fetch" product naming and for parity with the other ZenRows SDKs.
-	Fetch(ctx context.Context, targetURL string, params RequestParameters) (*Response, error)
+	Fetch(ctx context.Context, targetURL string, params *RequestParameters) (*Response, error)
 	// Extract fetches the given target URL and runs it through Extract, ZenRows' AI-powered
 	// structured extraction (beta).
-	Extract(ctx context.Context, targetURL string, params RequestParameters) (*Response, error)
+	Extract(ctx context.Context, targetURL string, params *RequestParameters) (*Response, error)
 	// Post sends a POST request to the ZenRows Fetch API to scrape the given target URL using the specified parameters.
-	Post(ctx context.Context, targetURL string, params RequestParameters) (*Response, error)
+	Post(ctx context.Context, targetURL string, params *RequestParameters, body any) (*Response, error)
 	// Put sends a PUT request to the ZenRows Fetch API to scrape the given target URL using the specified parameters.
-	Put(ctx context.Context, targetURL string, params RequestParameters) (*Response, error)
+	Put(ctx context.Context, targetURL string, params *RequestParameters, body any) (*Response, error)
+	// Escalate scrapes the given target URL with GET requests, climbing the escalation ladder of the client until a configuration
+	// succeeds.
+	Escalate(ctx context.Context, targetURL string) (*EscalationResult, error)
 }
+
+// Extractor is the subset of IClient used by ExtractAs, so it can be fed a fake in tests. Client, any IClient and
+// scraperapitest.MockClient implement it.
+type Extractor interface {
+	// Extract fetches the given target URL and runs it through Extract (see Client.Extract).
+	Extract(ctx context.Context, targetURL string, params *RequestParameters) (*Response, error)
+}
+
+// Client must implement IClient.
+var _ IClient = (*Client)(nil)
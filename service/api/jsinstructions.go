@@ -0,0 +1,432 @@
+package scraperapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// JSInstructionAction is the name of a single JavaScript instruction, as sent in the js_instructions parameter.
+//
+// See https://docs.zenrows.com/scraper-api/features/js-rendering#using-the-javascript-instructions for more information.
+type JSInstructionAction string
+
+const (
+	JSActionClick             JSInstructionAction = "click"
+	JSActionWait              JSInstructionAction = "wait"
+	JSActionWaitFor           JSInstructionAction = "wait_for"
+	JSActionWaitEvent         JSInstructionAction = "wait_event"
+	JSActionFill              JSInstructionAction = "fill"
+	JSActionCheck             JSInstructionAction = "check"
+	JSActionUncheck           JSInstructionAction = "uncheck"
+	JSActionSelectOption      JSInstructionAction = "select_option"
+	JSActionScrollX           JSInstructionAction = "scroll_x"
+	JSActionScrollY           JSInstructionAction = "scroll_y"
+	JSActionEvaluate          JSInstructionAction = "evaluate"
+	JSActionFrameClick        JSInstructionAction = "frame_click"
+	JSActionFrameWaitFor      JSInstructionAction = "frame_wait_for"
+	JSActionFrameFill         JSInstructionAction = "frame_fill"
+	JSActionFrameCheck        JSInstructionAction = "frame_check"
+	JSActionFrameUncheck      JSInstructionAction = "frame_uncheck"
+	JSActionFrameSelectOption JSInstructionAction = "frame_select_option"
+	JSActionFrameEvaluate     JSInstructionAction = "frame_evaluate"
+	JSActionFrameReveal       JSInstructionAction = "frame_reveal"
+	JSActionSolveCaptcha      JSInstructionAction = "solve_captcha"
+)
+
+// jsArgKind describes the shape of the value a JSInstructionAction expects on the wire.
+type jsArgKind int
+
+const (
+	jsArgSelector jsArgKind = iota // a non-empty string (selector, script or iframe)
+	jsArgMillis                    // a positive number of milliseconds
+	jsArgPixels                    // a number of pixels
+	jsArgEvent                     // one of AllJSWaitEvents
+	jsArgPair                      // [string, string]
+	jsArgTriple                    // [string, string, string]
+	jsArgCaptcha                   // {"type": JSCaptchaType, "options": {...}}
+)
+
+// jsInstructionArgs maps every known JSInstructionAction to the shape of its value.
+var jsInstructionArgs = map[JSInstructionAction]jsArgKind{
+	JSActionClick:             jsArgSelector,
+	JSActionWait:              jsArgMillis,
+	JSActionWaitFor:           jsArgSelector,
+	JSActionWaitEvent:         jsArgEvent,
+	JSActionFill:              jsArgPair,
+	JSActionCheck:             jsArgSelector,
+	JSActionUncheck:           jsArgSelector,
+	JSActionSelectOption:      jsArgPair,
+	JSActionScrollX:           jsArgPixels,
+	JSActionScrollY:           jsArgPixels,
+	JSActionEvaluate:          jsArgSelector,
+	JSActionFrameClick:        jsArgPair,
+	JSActionFrameWaitFor:      jsArgPair,
+	JSActionFrameFill:         jsArgTriple,
+	JSActionFrameCheck:        jsArgPair,
+	JSActionFrameUncheck:      jsArgPair,
+	JSActionFrameSelectOption: jsArgTriple,
+	JSActionFrameEvaluate:     jsArgPair,
+	JSActionFrameReveal:       jsArgSelector,
+	JSActionSolveCaptcha:      jsArgCaptcha,
+}
+
+var AllJSInstructionActions = func() map[JSInstructionAction]struct{} {
+	actions := make(map[JSInstructionAction]struct{}, len(jsInstructionArgs))
+	for action := range jsInstructionArgs {
+		actions[action] = struct{}{}
+	}
+	return actions
+}()
+
+// JSWaitEvent is a page lifecycle event that the wait_event instruction can wait for.
+type JSWaitEvent string
+
+const (
+	JSWaitEventLoad              JSWaitEvent = "load"
+	JSWaitEventDOMContentLoaded  JSWaitEvent = "domcontentloaded"
+	JSWaitEventNetworkIdle       JSWaitEvent = "networkidle"
+	JSWaitEventNetworkAlmostIdle JSWaitEvent = "networkalmostidle"
+)
+
+var AllJSWaitEvents = map[JSWaitEvent]struct{}{
+	JSWaitEventLoad:              {},
+	JSWaitEventDOMContentLoaded:  {},
+	JSWaitEventNetworkIdle:       {},
+	JSWaitEventNetworkAlmostIdle: {},
+}
+
+// JSCaptchaType is the kind of CAPTCHA the solve_captcha instruction should solve.
+type JSCaptchaType string
+
+const (
+	JSCaptchaTypeRecaptcha           JSCaptchaType = "recaptcha"
+	JSCaptchaTypeCloudflareTurnstile JSCaptchaType = "cloudflare_turnstile"
+)
+
+var AllJSCaptchaTypes = map[JSCaptchaType]struct{}{
+	JSCaptchaTypeRecaptcha:           {},
+	JSCaptchaTypeCloudflareTurnstile: {},
+}
+
+// maxJSInstructionWait is the maximum number of milliseconds a single wait instruction may pause for.
+const maxJSInstructionWait = 30_000
+
+// JSInstruction is a single step of a JavaScript instructions script. It serializes to the single-key JSON object the ZenRows Fetch
+// API expects, e.g. {"click": ".button"} or {"fill": ["#email", "me@example.com"]}.
+type JSInstruction struct {
+	Action JSInstructionAction
+	Value  any
+}
+
+// MarshalJSON implements json.Marshaler.
+func (i JSInstruction) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[JSInstructionAction]any{i.Action: i.Value})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts any single-key object; use ParseJSInstructions to also check the action and
+// its value against the known instruction set.
+func (i *JSInstruction) UnmarshalJSON(data []byte) error {
+	var raw map[JSInstructionAction]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 1 {
+		return fmt.Errorf("expected exactly one action per instruction, got %d", len(raw))
+	}
+	for action, value := range raw {
+		i.Action, i.Value = action, value
+	}
+	return nil
+}
+
+// JSInstructionsBuilder builds a JavaScript instructions script step by step. Create one with JSInstructions, and assign the result
+// of String to RequestParameters.JSInstructions.
+//
+//	params := &scraperapi.RequestParameters{
+//		JSRender:       true,
+//		JSInstructions: scraperapi.JSInstructions().Click("#accept").Fill("#q", "shoes").WaitFor(".results").String(),
+//	}
+type JSInstructionsBuilder struct {
+	instructions []JSInstruction
+}
+
+// JSInstructions returns an empty JSInstructionsBuilder.
+func JSInstructions() *JSInstructionsBuilder {
+	return &JSInstructionsBuilder{}
+}
+
+func (b *JSInstructionsBuilder) add(action JSInstructionAction, value any) *JSInstructionsBuilder {
+	b.instructions = append(b.instructions, JSInstruction{Action: action, Value: value})
+	return b
+}
+
+// Click clicks on the element matching the given selector.
+func (b *JSInstructionsBuilder) Click(selector string) *JSInstructionsBuilder {
+	return b.add(JSActionClick, selector)
+}
+
+// Wait pauses the script for the given number of milliseconds.
+func (b *JSInstructionsBuilder) Wait(milliseconds int) *JSInstructionsBuilder {
+	return b.add(JSActionWait, milliseconds)
+}
+
+// WaitFor waits until an element matching the given selector appears in the page.
+func (b *JSInstructionsBuilder) WaitFor(selector string) *JSInstructionsBuilder {
+	return b.add(JSActionWaitFor, selector)
+}
+
+// WaitEvent waits until the page reaches the given lifecycle event.
+func (b *JSInstructionsBuilder) WaitEvent(event JSWaitEvent) *JSInstructionsBuilder {
+	return b.add(JSActionWaitEvent, event)
+}
+
+// Fill types the given value into the input matching the given selector.
+func (b *JSInstructionsBuilder) Fill(selector, value string) *JSInstructionsBuilder {
+	return b.add(JSActionFill, []string{selector, value})
+}
+
+// Check checks the checkbox or radio button matching the given selector.
+func (b *JSInstructionsBuilder) Check(selector string) *JSInstructionsBuilder {
+	return b.add(JSActionCheck, selector)
+}
+
+// Uncheck unchecks the checkbox matching the given selector.
+func (b *JSInstructionsBuilder) Uncheck(selector string) *JSInstructionsBuilder {
+	return b.add(JSActionUncheck, selector)
+}
+
+// SelectOption selects the option with the given value in the dropdown matching the given selector.
+func (b *JSInstructionsBuilder) SelectOption(selector, value string) *JSInstructionsBuilder {
+	return b.add(JSActionSelectOption, []string{selector, value})
+}
+
+// ScrollX scrolls the page horizontally by the given number of pixels.
+func (b *JSInstructionsBuilder) ScrollX(pixels int) *JSInstructionsBuilder {
+	return b.add(JSActionScrollX, pixels)
+}
+
+// ScrollY scrolls the page vertically by the given number of pixels.
+func (b *JSInstructionsBuilder) ScrollY(pixels int) *JSInstructionsBuilder {
+	return b.add(JSActionScrollY, pixels)
+}
+
+// Evaluate runs the given JavaScript code in the page.
+func (b *JSInstructionsBuilder) Evaluate(script string) *JSInstructionsBuilder {
+	return b.add(JSActionEvaluate, script)
+}
+
+// FrameClick clicks on the element matching selector inside the iframe matching frame.
+func (b *JSInstructionsBuilder) FrameClick(frame, selector string) *JSInstructionsBuilder {
+	return b.add(JSActionFrameClick, []string{frame, selector})
+}
+
+// FrameWaitFor waits until an element matching selector appears inside the iframe matching frame.
+func (b *JSInstructionsBuilder) FrameWaitFor(frame, selector string) *JSInstructionsBuilder {
+	return b.add(JSActionFrameWaitFor, []string{frame, selector})
+}
+
+// FrameFill types value into the input matching selector inside the iframe matching frame.
+func (b *JSInstructionsBuilder) FrameFill(frame, selector, value string) *JSInstructionsBuilder {
+	return b.add(JSActionFrameFill, []string{frame, selector, value})
+}
+
+// FrameCheck checks the checkbox or radio button matching selector inside the iframe matching frame.
+func (b *JSInstructionsBuilder) FrameCheck(frame, selector string) *JSInstructionsBuilder {
+	return b.add(JSActionFrameCheck, []string{frame, selector})
+}
+
+// FrameUncheck unchecks the checkbox matching selector inside the iframe matching frame.
+func (b *JSInstructionsBuilder) FrameUncheck(frame, selector string) *JSInstructionsBuilder {
+	return b.add(JSActionFrameUncheck, []string{frame, selector})
+}
+
+// FrameSelectOption selects the option with the given value in the dropdown matching selector inside the iframe matching frame.
+func (b *JSInstructionsBuilder) FrameSelectOption(frame, selector, value string) *JSInstructionsBuilder {
+	return b.add(JSActionFrameSelectOption, []string{frame, selector, value})
+}
+
+// FrameEvaluate runs the given JavaScript code inside the iframe matching frame.
+func (b *JSInstructionsBuilder) FrameEvaluate(frame, script string) *JSInstructionsBuilder {
+	return b.add(JSActionFrameEvaluate, []string{frame, script})
+}
+
+// FrameReveal exposes the content of the iframe matching frame in the returned HTML.
+func (b *JSInstructionsBuilder) FrameReveal(frame string) *JSInstructionsBuilder {
+	return b.add(JSActionFrameReveal, frame)
+}
+
+// SolveCaptcha solves a CAPTCHA of the given type. Options are passed through as-is and may be nil.
+func (b *JSInstructionsBuilder) SolveCaptcha(captchaType JSCaptchaType, options map[string]any) *JSInstructionsBuilder {
+	value := map[string]any{"type": captchaType}
+	if len(options) > 0 {
+		value["options"] = options
+	}
+	return b.add(JSActionSolveCaptcha, value)
+}
+
+// Instructions returns a copy of the instructions added so far.
+func (b *JSInstructionsBuilder) Instructions() []JSInstruction {
+	return append([]JSInstruction(nil), b.instructions...)
+}
+
+// MarshalJSON implements json.Marshaler, serializing the instructions as a JSON array.
+func (b *JSInstructionsBuilder) MarshalJSON() ([]byte, error) {
+	if b.instructions == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(b.instructions)
+}
+
+// String returns the serialized instructions, ready to be assigned to RequestParameters.JSInstructions.
+func (b *JSInstructionsBuilder) String() string {
+	data, err := b.MarshalJSON()
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ParseJSInstructions parses a serialized JavaScript instructions script and checks every instruction against the known instruction
+// set. On failure, it returns an InvalidJSInstructionError pointing at the offending instruction.
+func ParseJSInstructions(raw string) ([]JSInstruction, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, InvalidJSInstructionError{Index: -1, Msg: "js_instructions must be a JSON array: " + err.Error()}
+	}
+
+	instructions := make([]JSInstruction, 0, len(items))
+	for idx, item := range items {
+		var instruction JSInstruction
+		if err := json.Unmarshal(item, &instruction); err != nil {
+			return nil, InvalidJSInstructionError{Index: idx, Msg: err.Error()}
+		}
+		if err := validateJSInstruction(instruction); err != nil {
+			return nil, InvalidJSInstructionError{Index: idx, Action: instruction.Action, Msg: err.Error()}
+		}
+		instructions = append(instructions, instruction)
+	}
+
+	return instructions, nil
+}
+
+// validateJSInstruction checks that the instruction is known and that its value has the expected shape.
+func validateJSInstruction(instruction JSInstruction) error {
+	kind, ok := jsInstructionArgs[instruction.Action]
+	if !ok {
+		return fmt.Errorf("unknown instruction %q", instruction.Action)
+	}
+
+	switch kind {
+	case jsArgSelector:
+		if s, isString := instruction.Value.(string); !isString || strings.TrimSpace(s) == "" {
+			return fmt.Errorf("%s expects a non-empty string", instruction.Action)
+		}
+	case jsArgMillis:
+		ms, isNumber := toFloat(instruction.Value)
+		if !isNumber || ms <= 0 || ms > maxJSInstructionWait || ms != math.Trunc(ms) {
+			return fmt.Errorf("%s expects a whole number of milliseconds between 1 and %d", instruction.Action, maxJSInstructionWait)
+		}
+	case jsArgPixels:
+		if px, isNumber := toFloat(instruction.Value); !isNumber || px != math.Trunc(px) {
+			return fmt.Errorf("%s expects a whole number of pixels", instruction.Action)
+		}
+	case jsArgEvent:
+		event, isString := toString(instruction.Value)
+		if _, known := AllJSWaitEvents[JSWaitEvent(event)]; !isString || !known {
+			return fmt.Errorf("%s expects one of load, domcontentloaded, networkidle, networkalmostidle", instruction.Action)
+		}
+	case jsArgPair:
+		if !isStringList(instruction.Value, 2) {
+			return fmt.Errorf("%s expects an array of 2 strings", instruction.Action)
+		}
+	case jsArgTriple:
+		if !isStringList(instruction.Value, 3) {
+			return fmt.Errorf("%s expects an array of 3 strings", instruction.Action)
+		}
+	case jsArgCaptcha:
+		return validateCaptchaValue(instruction.Value)
+	}
+
+	return nil
+}
+
+// validateCaptchaValue checks the {"type": ..., "options": {...}} value of a solve_captcha instruction.
+func validateCaptchaValue(value any) error {
+	var obj map[string]any
+	switch v := value.(type) {
+	case map[string]any:
+		obj = v
+	default:
+		// values built with JSInstructionsBuilder.SolveCaptcha hold typed values; normalize them through JSON
+		data, err := json.Marshal(v)
+		if err != nil || json.Unmarshal(data, &obj) != nil {
+			return fmt.Errorf("%s expects an object with a type", JSActionSolveCaptcha)
+		}
+	}
+
+	captchaType, _ := obj["type"].(string)
+	if _, ok := AllJSCaptchaTypes[JSCaptchaType(captchaType)]; !ok {
+		return fmt.Errorf("%s expects type to be one of recaptcha, cloudflare_turnstile", JSActionSolveCaptcha)
+	}
+	if options, ok := obj["options"]; ok {
+		if _, isObject := options.(map[string]any); !isObject {
+			return fmt.Errorf("%s expects options to be an object", JSActionSolveCaptcha)
+		}
+	}
+
+	return nil
+}
+
+// isStringList reports whether value is a list of exactly n strings. Every element but the last one must be non-empty: those are
+// selectors, while the last one may be a value that is legitimately empty (e.g. clearing an input).
+func isStringList(value any, n int) bool {
+	var items []string
+	switch v := value.(type) {
+	case []string:
+		items = v
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return false
+			}
+			items = append(items, s)
+		}
+	default:
+		return false
+	}
+
+	if len(items) != n {
+		return false
+	}
+	for _, item := range items[:n-1] {
+		if strings.TrimSpace(item) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func toString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case JSWaitEvent:
+		return string(v), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,98 @@
+package scraperapi_test
+
+import (
+	"errors"
+	"testing"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+func TestJSInstructionsBuilderSerializesToWireFormat(t *testing.T) {
+	got := scraperapi.JSInstructions().
+		Click("#accept").
+		Fill("#q", "shoes").
+		WaitFor(".results").
+		Wait(500).
+		ScrollY(1500).
+		WaitEvent(scraperapi.JSWaitEventNetworkIdle).
+		FrameFill("#login", "#user", "me").
+		String()
+
+	want := `[{"click":"#accept"},{"fill":["#q","shoes"]},{"wait_for":".results"},{"wait":500},{"scroll_y":1500},` +
+		`{"wait_event":"networkidle"},{"frame_fill":["#login","#user","me"]}]`
+	if got != want {
+		t.Fatalf("got %s\nwant %s", got, want)
+	}
+}
+
+func TestJSInstructionsBuilderEmptySerializesToEmptyArray(t *testing.T) {
+	if got := scraperapi.JSInstructions().String(); got != "[]" {
+		t.Fatalf("expected [], got %q", got)
+	}
+}
+
+func TestJSInstructionsBuilderOutputRoundTripsThroughParse(t *testing.T) {
+	raw := scraperapi.JSInstructions().
+		Click("a").
+		SelectOption("select", "2").
+		Evaluate("window.scrollTo(0, 0)").
+		SolveCaptcha(scraperapi.JSCaptchaTypeRecaptcha, map[string]any{"solve_inactive": true}).
+		FrameReveal("iframe").
+		String()
+
+	instructions, err := scraperapi.ParseJSInstructions(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(instructions) != 5 || instructions[3].Action != scraperapi.JSActionSolveCaptcha {
+		t.Fatalf("unexpected instructions: %+v", instructions)
+	}
+}
+
+func TestParseJSInstructionsReportsTheOffendingIndex(t *testing.T) {
+	cases := map[string]int{
+		`[{"click":"a"},{"clik":"b"}]`:                       1,
+		`[{"wait":0}]`:                                       0,
+		`[{"click":"a"},{"click":"b"},{"wait":"soon"}]`:      2,
+		`[{"fill":"#q"}]`:                                    0,
+		`[{"click":"a","wait":100}]`:                         0,
+		`[{"wait_event":"idle"}]`:                            0,
+		`[{"frame_fill":["#f","#i"]}]`:                       0,
+		`[{"solve_captcha":{"type":"hcaptcha"}}]`:            0,
+		`[{"click":"a"},{"scroll_y":1.5}]`:                   1,
+		`[{"click":"a"},{"click":"b"},{"click":"c"},"oops"]`: 3,
+	}
+
+	for raw, wantIndex := range cases {
+		_, err := scraperapi.ParseJSInstructions(raw)
+		var invalid scraperapi.InvalidJSInstructionError
+		if !errors.As(err, &invalid) {
+			t.Fatalf("%s: expected InvalidJSInstructionError, got %v", raw, err)
+		}
+		if invalid.Index != wantIndex {
+			t.Fatalf("%s: expected index %d, got %d (%v)", raw, wantIndex, invalid.Index, err)
+		}
+	}
+}
+
+func TestParseJSInstructionsRejectsNonArray(t *testing.T) {
+	_, err := scraperapi.ParseJSInstructions(`{"click":"a"}`)
+	var invalid scraperapi.InvalidJSInstructionError
+	if !errors.As(err, &invalid) || invalid.Index != -1 {
+		t.Fatalf("expected an InvalidJSInstructionError with index -1, got %v", err)
+	}
+}
+
+func TestValidateChecksJSInstructions(t *testing.T) {
+	p := &scraperapi.RequestParameters{JSRender: true, JSInstructions: `[{"click":"a"},{"hover":"b"}]`}
+
+	var invalid scraperapi.InvalidJSInstructionError
+	if err := p.Validate(); !errors.As(err, &invalid) || invalid.Index != 1 {
+		t.Fatalf("expected an InvalidJSInstructionError at index 1, got %v", err)
+	}
+
+	p.JSInstructions = scraperapi.JSInstructions().Click("a").Check("#terms").String()
+	if err := p.Validate(); err != nil {
+		t.Fatalf("expected builder output to validate, got %v", err)
+	}
+}
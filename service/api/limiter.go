@@ -0,0 +1,288 @@
+package scraperapi
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Priority is the priority lane a request waits in for a concurrency slot (see WithMaxConcurrentRequests). When a slot frees up,
+// it goes to the oldest request of the highest priority lane.
+type Priority int
+
+const (
+	// PriorityLow is for background work that should yield to everything else, e.g. crawls.
+	PriorityLow Priority = -1
+	// PriorityNormal is the priority of requests sent without ContextWithPriority.
+	PriorityNormal Priority = 0
+	// PriorityHigh is for interactive requests that should go ahead of everything else.
+	PriorityHigh Priority = 1
+)
+
+type priorityContextKey struct{}
+
+// ContextWithPriority returns a copy of ctx that sends requests with the given priority.
+//
+//	ctx := scraperapi.ContextWithPriority(context.Background(), scraperapi.PriorityLow)
+//	response, err := client.Get(ctx, "https://example.com", nil)
+func ContextWithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, priority)
+}
+
+// priorityFromContext returns the priority set on ctx with ContextWithPriority, or PriorityNormal.
+func priorityFromContext(ctx context.Context) Priority {
+	if priority, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+// ConcurrencyStats is a snapshot of the concurrency limiter of a Client.
+type ConcurrencyStats struct {
+	// Limit is the maximum number of concurrent requests, or 0 if the client is not limited.
+	Limit int
+	// InFlight is the number of requests holding a concurrency slot, streamed responses not yet closed included.
+	InFlight int
+	// Queued is the number of requests waiting for a concurrency slot.
+	Queued int
+	// QueuedByPriority is the number of requests waiting for a concurrency slot, by priority.
+	QueuedByPriority map[Priority]int
+	// PausedUntil is when the client resumes sending requests after a 429 Too Many Requests in adaptive mode (see
+	// WithAdaptiveConcurrency). It is zero if the client is not paused.
+	PausedUntil time.Time
+}
+
+// limiter limits the number of concurrent requests. Waiting for a slot respects the request context, and requests are granted
+// slots by priority, then in order of arrival.
+type limiter struct {
+	mu sync.Mutex
+	// limit is the maximum number of requests in flight, or 0 for no limit.
+	limit    int
+	inFlight int
+	lanes    map[Priority][]*waiter
+	// fastest is the shortest time a request took to get a response so far. Requests whose deadline is closer than that cannot
+	// make it, so they are dropped instead of taking a slot.
+	fastest time.Duration
+	// pausedUntil holds back every waiting request until then, after a 429 Too Many Requests in adaptive mode.
+	pausedUntil time.Time
+	// throttled is the number of consecutive 429 Too Many Requests responses.
+	throttled int
+}
+
+// waiter is a request waiting for a slot. ready is closed when the slot is granted.
+type waiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+func newLimiter(limit int) *limiter {
+	return &limiter{limit: limit, lanes: make(map[Priority][]*waiter)}
+}
+
+// acquire waits for a concurrency slot and returns the function that gives it back, which is safe to call more than once. It fails
+// if ctx is done, or its deadline cannot be met, before a slot is granted.
+func (l *limiter) acquire(ctx context.Context) (release func(), err error) {
+	if err := l.checkDeadline(ctx); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	if _, queued := l.nextLaneLocked(); !queued && l.hasCapacityLocked() {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.releaseFunc(), nil
+	}
+
+	w := &waiter{ready: make(chan struct{})}
+	priority := priorityFromContext(ctx)
+	l.lanes[priority] = append(l.lanes[priority], w)
+	l.grantLocked()
+	fastest := l.fastest
+	l.mu.Unlock()
+
+	// give up as soon as the deadline cannot be met anymore, rather than when it expires
+	var unreachable <-chan time.Time
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline && fastest > 0 {
+		timer := time.NewTimer(time.Until(deadline) - fastest)
+		defer timer.Stop()
+		unreachable = timer.C
+	}
+
+	select {
+	case <-w.ready:
+		if err := l.checkDeadline(ctx); err != nil {
+			l.release()
+			return nil, err
+		}
+		return l.releaseFunc(), nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-unreachable:
+		err = DeadlineUnreachableError{Remaining: time.Until(deadline), Expected: fastest}
+	}
+
+	l.mu.Lock()
+	if w.granted {
+		// the slot was granted while giving up, so hand it over to the next request
+		l.mu.Unlock()
+		l.release()
+	} else {
+		l.lanes[priority] = slices.DeleteFunc(l.lanes[priority], func(other *waiter) bool { return other == w })
+		l.mu.Unlock()
+	}
+	return nil, err
+}
+
+// checkDeadline returns an error if ctx is done, or its deadline is closer than the fastest response seen so far.
+func (l *limiter) checkDeadline(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return nil
+	}
+
+	l.mu.Lock()
+	fastest := l.fastest
+	l.mu.Unlock()
+
+	if remaining := time.Until(deadline); fastest > 0 && remaining < fastest {
+		return DeadlineUnreachableError{Remaining: remaining, Expected: fastest}
+	}
+	return nil
+}
+
+// observe records the time a request took to get a response.
+func (l *limiter) observe(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if d > 0 && (l.fastest == 0 || d < l.fastest) {
+		l.fastest = d
+	}
+}
+
+// releaseFunc returns a function that releases one slot, once.
+func (l *limiter) releaseFunc() func() {
+	var once sync.Once
+	return func() { once.Do(l.release) }
+}
+
+// release gives a slot back, and grants it to the next waiting request, if any.
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	l.grantLocked()
+}
+
+// hasCapacityLocked reports whether a slot is free. It must be called with l.mu held.
+func (l *limiter) hasCapacityLocked() bool {
+	if time.Now().Before(l.pausedUntil) {
+		return false
+	}
+	return l.limit <= 0 || l.inFlight < l.limit
+}
+
+// resize sets the limit from the concurrency limit of the plan and the slots it has left, as reported by a response. Slots used by
+// other clients sharing the plan are not available to this one, so the limit is the slots this client holds plus the slots left.
+// remaining is negative if unknown.
+func (l *limiter) resize(planLimit, remaining int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := planLimit
+	if remaining >= 0 {
+		limit = min(limit, l.inFlight+remaining)
+	}
+	l.limit = max(limit, 1)
+	l.grantLocked()
+}
+
+// throttle pauses the client after a 429 Too Many Requests response for the given time, or for an exponential backoff based on
+// base and maxWait if wait is 0. If the response did not report the concurrency limit, the limit is halved too. It returns the time
+// the client is paused for, and the resulting limit.
+func (l *limiter) throttle(wait, base, maxWait time.Duration, resized bool) (time.Duration, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.throttled++
+	if wait <= 0 {
+		wait = min(base<<min(l.throttled-1, 16), maxWait)
+	}
+	if !resized && l.limit > 1 {
+		l.limit /= 2
+	}
+
+	if until := time.Now().Add(wait); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+		time.AfterFunc(wait, func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.grantLocked()
+		})
+	}
+	return wait, l.limit
+}
+
+// unthrottle resets the backoff after a response that is not a 429 Too Many Requests.
+func (l *limiter) unthrottle() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.throttled = 0
+}
+
+// grantLocked grants the free slots to the waiting requests, by priority and then in order of arrival. It must be called with l.mu
+// held.
+func (l *limiter) grantLocked() {
+	for l.hasCapacityLocked() {
+		priority, ok := l.nextLaneLocked()
+		if !ok {
+			return
+		}
+
+		w := l.lanes[priority][0]
+		l.lanes[priority] = l.lanes[priority][1:]
+		w.granted = true
+		l.inFlight++
+		close(w.ready)
+	}
+}
+
+// nextLaneLocked returns the highest priority with waiting requests. It must be called with l.mu held.
+func (l *limiter) nextLaneLocked() (Priority, bool) {
+	var (
+		next  Priority
+		found bool
+	)
+	for priority, waiters := range l.lanes {
+		if len(waiters) > 0 && (!found || priority > next) {
+			next, found = priority, true
+		}
+	}
+	return next, found
+}
+
+// stats returns a snapshot of the limiter.
+func (l *limiter) stats() ConcurrencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := ConcurrencyStats{Limit: max(l.limit, 0), InFlight: l.inFlight, QueuedByPriority: make(map[Priority]int)}
+	if time.Now().Before(l.pausedUntil) {
+		stats.PausedUntil = l.pausedUntil
+	}
+	for priority, waiters := range l.lanes {
+		if len(waiters) > 0 {
+			stats.QueuedByPriority[priority] = len(waiters)
+			stats.Queued += len(waiters)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,134 @@
+package scraperapi_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+// waitForQueued waits until the client has n requests waiting for a concurrency slot.
+func waitForQueued(t *testing.T, client *scraperapi.Client, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for client.Concurrency().Queued != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d queued requests, got %+v", n, client.Concurrency())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestConcurrencyLimiterRespectsContextWhileQueued(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithMaxConcurrentRequests(1))
+	held, err := client.GetStream(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer held.Close()
+
+	if stats := client.Concurrency(); stats.Limit != 1 || stats.InFlight != 1 {
+		t.Fatalf("expected the streamed response to hold the only slot, got %+v", stats)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.Get(ctx, "https://example.com", nil)
+		errs <- err
+	}()
+
+	waitForQueued(t, client, 1)
+	cancel()
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if stats := client.Concurrency(); stats.Queued != 0 || stats.InFlight != 1 {
+		t.Fatalf("expected the cancelled request to leave the queue, got %+v", stats)
+	}
+}
+
+func TestConcurrencyLimiterGrantsSlotsByPriority(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		order = append(order, r.URL.Query().Get("url"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithMaxConcurrentRequests(1))
+	held, err := client.GetStream(context.Background(), "https://example.com/held", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	send := func(target string, priority scraperapi.Priority) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.Get(scraperapi.ContextWithPriority(context.Background(), priority), target, nil)
+		}()
+	}
+
+	send("https://example.com/low", scraperapi.PriorityLow)
+	waitForQueued(t, client, 1)
+	send("https://example.com/normal", scraperapi.PriorityNormal)
+	waitForQueued(t, client, 2)
+	send("https://example.com/high", scraperapi.PriorityHigh)
+	waitForQueued(t, client, 3)
+
+	stats := client.Concurrency()
+	if stats.QueuedByPriority[scraperapi.PriorityLow] != 1 || stats.QueuedByPriority[scraperapi.PriorityHigh] != 1 {
+		t.Fatalf("unexpected queued requests by priority: %+v", stats)
+	}
+
+	_ = held.Close()
+	wg.Wait()
+
+	want := []string{"https://example.com/held", "https://example.com/high", "https://example.com/normal", "https://example.com/low"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("got %v, want %v", order, want)
+	}
+}
+
+func TestConcurrencyLimiterDropsRequestsWhoseDeadlineCannotBeMet(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"))
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Get(ctx, "https://example.com", nil)
+	var unreachable scraperapi.DeadlineUnreachableError
+	if !errors.As(err, &unreachable) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineUnreachableError, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected the request to be dropped before being sent, got %d calls", calls.Load())
+	}
+}
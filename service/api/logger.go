@@ -1,5 +1,20 @@
 package scraperapi
 
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// redactedValue replaces the API key in log records.
+const redactedValue = "[REDACTED]"
+
+// apiKeyParamPattern matches the apikey query parameter of a URL, whatever its value.
+var apiKeyParamPattern = regexp.MustCompile(`(?i)(\bapikey=)[^&\s"']+`)
+
 // noopLogger is a logger that does nothing
 type noopLogger struct{}
 
@@ -14,3 +29,105 @@ func (l noopLogger) Warnf(_ string, _ ...any) {
 func (l noopLogger) Debugf(_ string, _ ...any) {
 	// no-op
 }
+
+// WithLogger returns an Option which configures the logger of the ZenRows Fetch API client. The client logs the start and end of
+// every attempt to send a request at debug level, and retries and backoff waits at info level. The API key is always redacted,
+// whatever attribute or message it ends up in. Defaults to nil (no logging).
+func WithLogger(logger *slog.Logger) Option {
+	return newFuncDialOption(func(o *options) {
+		o.logger = logger
+	})
+}
+
+// newLogger returns a logger that redacts the API key from every record sent to logger, or a logger that discards every record if
+// logger is nil.
+func newLogger(logger *slog.Logger, apiKey string) *slog.Logger {
+	if logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return slog.New(&redactingHandler{next: logger.Handler(), secret: apiKey})
+}
+
+// redactingHandler is a slog.Handler that redacts the API key from the message and attributes of every record: both its value and
+// the apikey query parameter of URLs.
+type redactingHandler struct {
+	next   slog.Handler
+	secret string
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redact(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = h.redactAttr(attr)
+	}
+	return &redactingHandler{next: h.next.WithAttrs(redacted), secret: h.secret}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{next: h.next.WithGroup(name), secret: h.secret}
+}
+
+// redactAttr redacts the API key from an attribute, recursively for groups.
+func (h *redactingHandler) redactAttr(attr slog.Attr) slog.Attr {
+	if strings.EqualFold(attr.Key, apiKeyParamName) || strings.EqualFold(attr.Key, "X-API-Key") {
+		return slog.String(attr.Key, redactedValue)
+	}
+
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, h.redact(value.String()))
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, member := range group {
+			redacted[i] = h.redactAttr(member)
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindAny:
+		if header, ok := value.Any().(http.Header); ok {
+			return slog.Any(attr.Key, h.redactHeader(header))
+		}
+		if s := fmt.Sprint(value.Any()); h.redact(s) != s {
+			return slog.String(attr.Key, h.redact(s))
+		}
+	default:
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+// redactHeader returns a copy of header with the API key redacted.
+func (h *redactingHandler) redactHeader(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for key, values := range header {
+		if strings.EqualFold(key, "X-API-Key") {
+			redacted[key] = []string{redactedValue}
+			continue
+		}
+		for _, v := range values {
+			redacted[key] = append(redacted[key], h.redact(v))
+		}
+	}
+	return redacted
+}
+
+// redact replaces the API key and the value of apikey query parameters in s.
+func (h *redactingHandler) redact(s string) string {
+	if h.secret != "" {
+		s = strings.ReplaceAll(s, h.secret, redactedValue)
+	}
+	return apiKeyParamPattern.ReplaceAllString(s, "${1}"+redactedValue)
+}
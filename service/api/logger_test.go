@@ -0,0 +1,78 @@
+package scraperapi_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+const secretAPIKey = "s3cr3t-api-key"
+
+func TestWithLoggerLogsRequestsAndRetries(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-Request-Cost", "1")
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey(secretAPIKey),
+		scraperapi.WithMaxRetryCount(1),
+		scraperapi.WithRetryWaitTime(time.Millisecond),
+		scraperapi.WithLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))),
+	)
+
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs := buf.String()
+	for _, event := range []string{"scraperapi: request start", "scraperapi: request end", "scraperapi: retrying request"} {
+		if strings.Count(logs, event) == 0 {
+			t.Errorf("expected a %q event, got:\n%s", event, logs)
+		}
+	}
+	if strings.Count(logs, "scraperapi: request end") != 2 {
+		t.Errorf("expected an end event per attempt, got:\n%s", logs)
+	}
+}
+
+func TestWithLoggerRedactsTheAPIKey(t *testing.T) {
+	// a closed server makes the request fail with an error that carries the full request URL, API key included
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey(secretAPIKey),
+		scraperapi.WithLogger(logger),
+	)
+
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err == nil {
+		t.Fatal("expected the request to fail")
+	}
+
+	logs := buf.String()
+	if !strings.Contains(logs, "scraperapi: request failed") {
+		t.Fatalf("expected a failure event, got:\n%s", logs)
+	}
+	if strings.Contains(logs, secretAPIKey) {
+		t.Fatalf("expected the API key to be redacted, got:\n%s", logs)
+	}
+}
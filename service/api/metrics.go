@@ -0,0 +1,241 @@
+package scraperapi
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metric names reported by the client to its Metrics.
+const (
+	// MetricRequests counts the attempts to send a request, by method and status code ("error" if the request could not be sent).
+	MetricRequests = "zenrows_fetch_requests_total"
+	// MetricRequestDuration is the duration of the attempts to send a request, by method.
+	MetricRequestDuration = "zenrows_fetch_request_duration_seconds"
+	// MetricRetries counts the retries, by method.
+	MetricRetries = "zenrows_fetch_retries_total"
+	// MetricQueueWait is the time requests waited for a concurrency slot.
+	MetricQueueWait = "zenrows_fetch_queue_wait_seconds"
+	// MetricCredits counts the credits charged, as reported by the responses.
+	MetricCredits = "zenrows_fetch_credits_total"
+)
+
+// Metrics receives the measurements of a client, e.g. to export them to a monitoring system. The interface is the same as the one
+// of the Batch API SDK, so a single implementation can serve both clients. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// AddCounter adds delta to the counter with the given name and labels.
+	AddCounter(name string, labels map[string]string, delta float64)
+	// ObserveDuration records a duration in the summary with the given name and labels.
+	ObserveDuration(name string, labels map[string]string, d time.Duration)
+}
+
+// WithMetrics returns an Option which configures where the client reports its metrics (see the Metric constants). See
+// NewMemoryMetrics for an in-memory implementation that serves them in the Prometheus text format. Defaults to nil (no metrics).
+func WithMetrics(metrics Metrics) Option {
+	return newFuncDialOption(func(o *options) {
+		o.metrics = metrics
+	})
+}
+
+// noopMetrics is a Metrics that discards every measurement.
+type noopMetrics struct{}
+
+func (noopMetrics) AddCounter(string, map[string]string, float64) {
+	// no-op
+}
+
+func (noopMetrics) ObserveDuration(string, map[string]string, time.Duration) {
+	// no-op
+}
+
+// CounterValue is the value of a counter in a MetricsSnapshot.
+type CounterValue struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// DurationValue is the value of a duration summary in a MetricsSnapshot.
+type DurationValue struct {
+	Name   string
+	Labels map[string]string
+	Count  int64
+	Sum    time.Duration
+}
+
+// MetricsSnapshot is a copy of the metrics recorded by a MemoryMetrics, sorted by name and labels.
+type MetricsSnapshot struct {
+	Counters  []CounterValue
+	Durations []DurationValue
+}
+
+// Counter returns the sum of the counters with the given name whose labels include the given ones.
+func (s MetricsSnapshot) Counter(name string, labels map[string]string) float64 {
+	var sum float64
+	for _, c := range s.Counters {
+		if c.Name == name && hasLabels(c.Labels, labels) {
+			sum += c.Value
+		}
+	}
+	return sum
+}
+
+// Duration returns the count and sum of the duration summaries with the given name whose labels include the given ones.
+func (s MetricsSnapshot) Duration(name string, labels map[string]string) (count int64, sum time.Duration) {
+	for _, d := range s.Durations {
+		if d.Name == name && hasLabels(d.Labels, labels) {
+			count += d.Count
+			sum += d.Sum
+		}
+	}
+	return count, sum
+}
+
+// hasLabels reports whether labels include every label of want.
+func hasLabels(labels, want map[string]string) bool {
+	for k, v := range want {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// MemoryMetrics is an in-memory Metrics. It serves the metrics in the Prometheus text exposition format as an http.Handler, e.g.
+// on a /metrics endpoint. It is safe for concurrent use.
+type MemoryMetrics struct {
+	mu        sync.Mutex
+	counters  map[string]*CounterValue
+	durations map[string]*DurationValue
+}
+
+// NewMemoryMetrics creates and returns a new, empty MemoryMetrics.
+func NewMemoryMetrics() *MemoryMetrics {
+	return &MemoryMetrics{counters: make(map[string]*CounterValue), durations: make(map[string]*DurationValue)}
+}
+
+// AddCounter implements Metrics.
+func (m *MemoryMetrics) AddCounter(name string, labels map[string]string, delta float64) {
+	key := seriesKey(name, labels)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter, ok := m.counters[key]
+	if !ok {
+		counter = &CounterValue{Name: name, Labels: maps.Clone(labels)}
+		m.counters[key] = counter
+	}
+	counter.Value += delta
+}
+
+// ObserveDuration implements Metrics.
+func (m *MemoryMetrics) ObserveDuration(name string, labels map[string]string, d time.Duration) {
+	key := seriesKey(name, labels)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	duration, ok := m.durations[key]
+	if !ok {
+		duration = &DurationValue{Name: name, Labels: maps.Clone(labels)}
+		m.durations[key] = duration
+	}
+	duration.Count++
+	duration.Sum += d
+}
+
+// Snapshot returns a copy of the metrics recorded so far.
+func (m *MemoryMetrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var snapshot MetricsSnapshot
+	for _, counter := range m.counters {
+		c := *counter
+		c.Labels = maps.Clone(c.Labels)
+		snapshot.Counters = append(snapshot.Counters, c)
+	}
+	for _, duration := range m.durations {
+		d := *duration
+		d.Labels = maps.Clone(d.Labels)
+		snapshot.Durations = append(snapshot.Durations, d)
+	}
+
+	slices.SortFunc(snapshot.Counters, func(a, b CounterValue) int { return compareSeries(a.Name, a.Labels, b.Name, b.Labels) })
+	slices.SortFunc(snapshot.Durations, func(a, b DurationValue) int { return compareSeries(a.Name, a.Labels, b.Name, b.Labels) })
+	return snapshot
+}
+
+// compareSeries orders series by name, and then by labels.
+func compareSeries(aName string, aLabels map[string]string, bName string, bLabels map[string]string) int {
+	if c := strings.Compare(aName, bName); c != 0 {
+		return c
+	}
+	return strings.Compare(formatLabels(aLabels), formatLabels(bLabels))
+}
+
+// WritePrometheus writes the metrics recorded so far to w, in the Prometheus text exposition format. Counters are exposed as
+// counters, and durations as summaries in seconds, without quantiles.
+func (m *MemoryMetrics) WritePrometheus(w io.Writer) error {
+	snapshot := m.Snapshot()
+
+	var b strings.Builder
+	lastName := ""
+	for _, c := range snapshot.Counters {
+		if c.Name != lastName {
+			fmt.Fprintf(&b, "# TYPE %s counter\n", c.Name)
+			lastName = c.Name
+		}
+		fmt.Fprintf(&b, "%s%s %s\n", c.Name, formatLabels(c.Labels), formatFloat(c.Value))
+	}
+	for _, d := range snapshot.Durations {
+		if d.Name != lastName {
+			fmt.Fprintf(&b, "# TYPE %s summary\n", d.Name)
+			lastName = d.Name
+		}
+		fmt.Fprintf(&b, "%s_sum%s %s\n", d.Name, formatLabels(d.Labels), formatFloat(d.Sum.Seconds()))
+		fmt.Fprintf(&b, "%s_count%s %d\n", d.Name, formatLabels(d.Labels), d.Count)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP serves the metrics recorded so far in the Prometheus text exposition format.
+func (m *MemoryMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WritePrometheus(w)
+}
+
+// seriesKey returns the key of the series with the given name and labels, the same whatever the order of the labels.
+func seriesKey(name string, labels map[string]string) string {
+	return name + formatLabels(labels)
+}
+
+// labelValueEscaper escapes label values as in the Prometheus text exposition format.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels formats labels as in the Prometheus text exposition format, sorted by name: {a="1",b="2"}.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(labels))
+	for _, k := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, k+`="`+labelValueEscaper.Replace(labels[k])+`"`)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatFloat formats a sample value as in the Prometheus text exposition format.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,92 @@
+package scraperapi_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+func TestWithMetricsRecordsRequestsRetriesAndCredits(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-Request-Cost", "5")
+	}))
+	defer server.Close()
+
+	metrics := scraperapi.NewMemoryMetrics()
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithMaxRetryCount(1),
+		scraperapi.WithRetryWaitTime(time.Millisecond),
+		scraperapi.WithMetrics(metrics),
+	)
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot := metrics.Snapshot()
+	if got := snapshot.Counter(scraperapi.MetricRequests, map[string]string{"method": "GET"}); got != 2 {
+		t.Errorf("expected 2 attempts, got %g", got)
+	}
+	if got := snapshot.Counter(scraperapi.MetricRequests, map[string]string{"status": "429"}); got != 1 {
+		t.Errorf("expected 1 attempt with a 429, got %g", got)
+	}
+	if got := snapshot.Counter(scraperapi.MetricRetries, nil); got != 1 {
+		t.Errorf("expected 1 retry, got %g", got)
+	}
+	if got := snapshot.Counter(scraperapi.MetricCredits, nil); got != 5 {
+		t.Errorf("expected 5 credits, got %g", got)
+	}
+	if count, _ := snapshot.Duration(scraperapi.MetricQueueWait, nil); count != 1 {
+		t.Errorf("expected 1 queue wait, got %d", count)
+	}
+	if count, _ := snapshot.Duration(scraperapi.MetricRequestDuration, nil); count != 2 {
+		t.Errorf("expected 2 request durations, got %d", count)
+	}
+}
+
+func TestMemoryMetricsServesPrometheusText(t *testing.T) {
+	metrics := scraperapi.NewMemoryMetrics()
+	metrics.AddCounter("requests_total", map[string]string{"status": "200", "method": "GET"}, 2)
+	metrics.AddCounter("requests_total", map[string]string{"method": "GET", "status": "200"}, 1)
+	metrics.AddCounter("requests", nil, 1)
+	metrics.ObserveDuration("wait_seconds", map[string]string{"path": `a"b`}, 1500*time.Millisecond)
+
+	server := httptest.NewServer(metrics)
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", res.Header.Get("Content-Type"))
+	}
+	want := strings.Join([]string{
+		"# TYPE requests counter",
+		"requests 1",
+		"# TYPE requests_total counter",
+		`requests_total{method="GET",status="200"} 3`,
+		"# TYPE wait_seconds summary",
+		`wait_seconds_sum{path="a\"b"} 1.5`,
+		`wait_seconds_count{path="a\"b"} 1`,
+	}, "\n") + "\n"
+	if string(body) != want {
+		t.Fatalf("unexpected exposition:\n%s\nwant:\n%s", body, want)
+	}
+}
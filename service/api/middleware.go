@@ -0,0 +1,49 @@
+package scraperapi
+
+import "context"
+
+// Request is a request to the ZenRows Fetch API, as seen by middleware. Middleware can modify it before calling the next handler.
+type Request struct {
+	Method    string
+	TargetURL string
+	Params    *RequestParameters
+	Body      any
+	// Stream is true for requests sent with Client.ScrapeStream, whose response body is not buffered.
+	Stream bool
+	// Attempt is the attempt number, starting at 1, for middleware configured with WithAttemptMiddleware. It is 0 for middleware
+	// configured with WithMiddleware, which sees the request once, retries included.
+	Attempt int
+}
+
+// Handler sends a request to the ZenRows Fetch API, and returns its response.
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler, e.g. to observe or modify requests and their responses. It can also answer a request without calling
+// next, e.g. from a cache.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware returns an Option which adds middleware around the requests of the client. It runs outside the retry loop, so it
+// sees every request once, with its final response or error; it also runs before the request is validated, reserved from the
+// budget, and waits for a concurrency slot. The first middleware added is the outermost.
+func WithMiddleware(middleware ...Middleware) Option {
+	return newFuncDialOption(func(o *options) {
+		o.middleware = append(o.middleware, middleware...)
+	})
+}
+
+// WithAttemptMiddleware returns an Option which adds middleware around every attempt to send a request, inside the retry loop: it
+// sees every retry (see Request.Attempt), with the response or error that the retry policy decides on. The first middleware added
+// is the outermost.
+func WithAttemptMiddleware(middleware ...Middleware) Option {
+	return newFuncDialOption(func(o *options) {
+		o.attemptMiddleware = append(o.attemptMiddleware, middleware...)
+	})
+}
+
+// chainMiddleware wraps handler with middleware, the first one being the outermost.
+func chainMiddleware(handler Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
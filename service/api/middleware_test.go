@@ -0,0 +1,98 @@
+package scraperapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+func TestMiddlewareRunsOutsideTheRetryLoop(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var calls, attempts []string
+	outer := func(name string) scraperapi.Middleware {
+		return func(next scraperapi.Handler) scraperapi.Handler {
+			return func(ctx context.Context, req *scraperapi.Request) (*scraperapi.Response, error) {
+				calls = append(calls, name+" "+req.Method+" "+req.TargetURL)
+				return next(ctx, req)
+			}
+		}
+	}
+	perAttempt := func(next scraperapi.Handler) scraperapi.Handler {
+		return func(ctx context.Context, req *scraperapi.Request) (*scraperapi.Response, error) {
+			res, err := next(ctx, req)
+			attempts = append(attempts, res.Status())
+			if req.Attempt != len(attempts) {
+				t.Errorf("expected attempt %d, got %d", len(attempts), req.Attempt)
+			}
+			return res, err
+		}
+	}
+
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithMaxRetryCount(2),
+		scraperapi.WithRetryWaitTime(time.Millisecond),
+		scraperapi.WithMiddleware(outer("first"), outer("second")),
+		scraperapi.WithAttemptMiddleware(perAttempt),
+	)
+
+	res, err := client.Get(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsSuccess() {
+		t.Fatalf("expected the retried request to succeed, got %d", res.StatusCode())
+	}
+	if len(calls) != 2 || calls[0] != "first GET https://example.com" || calls[1] != "second GET https://example.com" {
+		t.Fatalf("expected the middleware to run once, in order, got %v", calls)
+	}
+	if len(attempts) != 2 || attempts[0] != "429 Too Many Requests" || attempts[1] != "200 OK" {
+		t.Fatalf("expected the attempt middleware to see every attempt, got %v", attempts)
+	}
+}
+
+func TestMiddlewareCanModifyTheRequest(t *testing.T) {
+	var gotJSRender string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotJSRender = r.URL.Query().Get("js_render")
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithMiddleware(func(next scraperapi.Handler) scraperapi.Handler {
+			return func(ctx context.Context, req *scraperapi.Request) (*scraperapi.Response, error) {
+				params := scraperapi.RequestParameters{}
+				if req.Params != nil {
+					params = *req.Params
+				}
+				params.JSRender = true
+				req.Params = &params
+				return next(ctx, req)
+			}
+		}),
+	)
+
+	if _, err := client.Get(context.Background(), "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotJSRender != "true" {
+		t.Fatalf("expected the middleware to enable js_render, got %q", gotJSRender)
+	}
+}
@@ -1,6 +1,7 @@
 package scraperapi
 
 import (
+	"log/slog"
 	"net/http"
 	"os"
 	"time"
@@ -28,11 +29,41 @@ type options struct {
 	apiKey string
 	// retryOptions holds the configuration for the retry mechanism of the ZenRows Fetch API client
 	retryOptions retryOptions
+	// retryPolicy decides whether failed requests are retried. Defaults to nil (a DefaultRetryPolicy built from retryOptions).
+	retryPolicy RetryPolicy
 	// maxConcurrentRequests is the maximum number of concurrent requests that can be handled by the ZenRows Fetch API client at a time
 	maxConcurrentRequests int
+	// adaptiveConcurrency makes the client follow the concurrency limit reported by the ZenRows Fetch API. Defaults to false.
+	adaptiveConcurrency bool
+	// budget caps the credits the client can spend. Defaults to nil (no limit).
+	budget *Budget
+	// escalationLadder is the ladder of request parameters Client.Escalate tries. Defaults to DefaultEscalationLadder.
+	escalationLadder []RequestParameters
+	// escalationStore remembers the configuration that worked for each domain. Defaults to nil (an in-memory store).
+	escalationStore EscalationStore
+	// escalationTTL is how long escalationStore remembers configurations. Defaults to 24 hours.
+	escalationTTL time.Duration
+	// middleware wraps every request, outside the retry loop. Defaults to none.
+	middleware []Middleware
+	// attemptMiddleware wraps every attempt to send a request, inside the retry loop. Defaults to none.
+	attemptMiddleware []Middleware
+	// logger receives the structured logs of the client. Defaults to nil (no logging).
+	logger *slog.Logger
+	// metrics receives the metrics of the client. Defaults to nil (no metrics).
+	metrics Metrics
+	// cache stores responses, to answer the same requests without sending them again. Defaults to nil (no cache).
+	cache Cache
+	// cacheTTL is how long cached responses are served. Defaults to 24 hours.
+	cacheTTL time.Duration
+	// cacheErrorResponses makes the cache store error responses too. Defaults to false.
+	cacheErrorResponses bool
+	// httpClient sends the requests. Defaults to nil (a client with a cookie jar and the default transport).
+	httpClient *http.Client
+	// maxStreamBodySize is the maximum number of bytes that can be read from a streamed response body. Defaults to 0 (no limit).
+	maxStreamBodySize int64
 }
 
-// retryOptions holds the configuration for the retry mechanism of the ZenRows Fetch API client. Only response status codes in
+// retryOptions holds the configuration for the DefaultRetryPolicy of the ZenRows Fetch API client. Only response status codes in
 // the retryableStatusCodes list will be retried.
 type retryOptions struct {
 	// maxRetryCount is the maximum number of retries to perform. If set to a non-zero value, the client will retry the request up to
@@ -119,3 +150,20 @@ func WithMaxConcurrentRequests(maxConcurrentRequests int) Option {
 		o.maxConcurrentRequests = maxConcurrentRequests
 	})
 }
+
+// WithMaxStreamBodySize returns an Option which caps the number of bytes that can be read from the body of a response returned by
+// Client.ScrapeStream. Reading past the cap fails with a BodyTooLargeError. Defaults to 0 (no limit).
+func WithMaxStreamBodySize(maxStreamBodySize int64) Option {
+	return newFuncDialOption(func(o *options) {
+		o.maxStreamBodySize = maxStreamBodySize
+	})
+}
+
+// WithHTTPClient returns an Option which configures the HTTP client that sends the requests, e.g. to set a proxy, or a transport
+// that records and replays them in tests (see the recorder package). The client is copied, so it is not modified; its Timeout
+// applies to every attempt. Defaults to a client with a cookie jar and the default transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return newFuncDialOption(func(o *options) {
+		o.httpClient = httpClient
+	})
+}
@@ -0,0 +1,299 @@
+package scraperapi
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/url"
+	"slices"
+)
+
+// Outputs is the typed result of a request sent with RequestParameters.Outputs, with one field per OutputType.
+//
+// URL-valued outputs (Links, Images, Audios, Videos and Favicon) are resolved against the target URL, so relative links such as
+// "/about" come back absolute.
+//
+// See https://docs.zenrows.com/scraper-api/features/output#output-filters for more information.
+type Outputs struct {
+	Emails       []string
+	PhoneNumbers []string
+	Headings     []string
+	Images       []string
+	Audios       []string
+	Videos       []string
+	Links        []string
+	Tables       []Table
+	Menus        []string
+	Hashtags     []string
+	Metadata     map[string]string
+	Favicon      string
+
+	// Missing lists the requested outputs that the response did not include.
+	Missing []OutputType
+}
+
+// Table is a table extracted by OutputTypeTables.
+type Table struct {
+	// Columns holds the table heading, if the table has one.
+	Columns []string
+	// Rows holds the table cells, row by row. When the table has a heading, cells are in the same order as Columns.
+	Rows [][]string
+}
+
+// WriteCSV writes the table as CSV to w, with Columns as the header row when the table has a heading.
+func (t Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if len(t.Columns) > 0 {
+		if err := writer.Write(t.Columns); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// CSV returns the table encoded as CSV. See Table.WriteCSV.
+func (t Table) CSV() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.WriteCSV(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// wireTable is a table as the ZenRows Fetch API returns it: its heading plus one object per row keyed by column name (or, for
+// tables without a heading, one array per row).
+type wireTable struct {
+	Heading []string          `json:"heading"`
+	Content []json.RawMessage `json:"content"`
+}
+
+// Outputs method decodes the body of a response sent with RequestParameters.Outputs set into an Outputs. It returns an
+// UnexpectedContentError if the body is not an outputs result, e.g. a problem+json body, or if the response is streamed (see
+// Client.ScrapeStream).
+func (r *Response) Outputs() (*Outputs, error) {
+	if r.IsError() {
+		return nil, r.unexpectedContent("outputs result")
+	}
+	if err := r.streamedContent("outputs result"); err != nil {
+		return nil, err
+	}
+
+	var raw map[OutputType]json.RawMessage
+	if err := json.Unmarshal(r.Body(), &raw); err != nil {
+		return nil, UnexpectedContentError{Expected: "outputs result", ContentType: r.Header().Get("Content-Type"), Err: err}
+	}
+
+	outputs := &Outputs{}
+	lists := map[OutputType]*[]string{
+		OutputTypeEmails:       &outputs.Emails,
+		OutputTypePhoneNumbers: &outputs.PhoneNumbers,
+		OutputTypeHeadings:     &outputs.Headings,
+		OutputTypeImages:       &outputs.Images,
+		OutputTypeAudios:       &outputs.Audios,
+		OutputTypeVideos:       &outputs.Videos,
+		OutputTypeLinks:        &outputs.Links,
+		OutputTypeMenus:        &outputs.Menus,
+		OutputTypeHashtags:     &outputs.Hashtags,
+	}
+	for outputType, dst := range lists {
+		if value, ok := raw[outputType]; ok {
+			list, err := decodeStringList(value)
+			if err != nil {
+				return nil, fmt.Errorf("outputs: decode %s: %w", outputType, err)
+			}
+			*dst = list
+		}
+	}
+
+	if value, ok := raw[OutputTypeTables]; ok {
+		tables, err := decodeTables(value)
+		if err != nil {
+			return nil, fmt.Errorf("outputs: decode %s: %w", OutputTypeTables, err)
+		}
+		outputs.Tables = tables
+	}
+
+	if value, ok := raw[OutputTypeMetadata]; ok {
+		metadata, err := decodeStringMap(value)
+		if err != nil {
+			return nil, fmt.Errorf("outputs: decode %s: %w", OutputTypeMetadata, err)
+		}
+		outputs.Metadata = metadata
+	}
+
+	if value, ok := raw[OutputTypeFavicon]; ok {
+		favicons, err := decodeStringList(value)
+		if err != nil {
+			return nil, fmt.Errorf("outputs: decode %s: %w", OutputTypeFavicon, err)
+		}
+		if len(favicons) > 0 {
+			outputs.Favicon = favicons[0]
+		}
+	}
+
+	r.resolveOutputURLs(outputs)
+	outputs.Missing = r.missingOutputs(raw)
+
+	return outputs, nil
+}
+
+// resolveOutputURLs resolves the URL-valued outputs against the target URL.
+func (r *Response) resolveOutputURLs(outputs *Outputs) {
+	base, err := url.Parse(r.targetURL)
+	if err != nil || r.targetURL == "" {
+		return
+	}
+
+	resolve := func(ref string) string {
+		parsed, parseErr := url.Parse(ref)
+		if parseErr != nil {
+			return ref
+		}
+		return base.ResolveReference(parsed).String()
+	}
+	for _, list := range []*[]string{&outputs.Links, &outputs.Images, &outputs.Audios, &outputs.Videos} {
+		for i, ref := range *list {
+			(*list)[i] = resolve(ref)
+		}
+	}
+	if outputs.Favicon != "" {
+		outputs.Favicon = resolve(outputs.Favicon)
+	}
+}
+
+// missingOutputs returns the requested outputs absent from the response, in the order they were requested. OutputTypeAll stands
+// for every output type.
+func (r *Response) missingOutputs(raw map[OutputType]json.RawMessage) []OutputType {
+	if r.params == nil {
+		return nil
+	}
+
+	requested := r.params.Outputs
+	if slices.Contains(requested, OutputTypeAll) {
+		requested = make([]OutputType, 0, len(AllOutputTypes))
+		for outputType := range AllOutputTypes {
+			if outputType != OutputTypeAll {
+				requested = append(requested, outputType)
+			}
+		}
+		slices.Sort(requested)
+	}
+
+	var missing []OutputType
+	for _, outputType := range requested {
+		if _, ok := raw[outputType]; !ok && !slices.Contains(missing, outputType) {
+			missing = append(missing, outputType)
+		}
+	}
+	return missing
+}
+
+// decodeStringList decodes a list of strings, tolerating a single string and, for grouped outputs such as headings keyed by
+// level, an object of lists (flattened in key order).
+func decodeStringList(value json.RawMessage) ([]string, error) {
+	var decoded any
+	if err := json.Unmarshal(value, &decoded); err != nil {
+		return nil, err
+	}
+
+	var list []string
+	var flatten func(v any)
+	flatten = func(v any) {
+		switch item := v.(type) {
+		case nil:
+		case string:
+			list = append(list, item)
+		case []any:
+			for _, elem := range item {
+				flatten(elem)
+			}
+		case map[string]any:
+			for _, key := range sortedKeys(item) {
+				flatten(item[key])
+			}
+		default:
+			list = append(list, fmt.Sprint(item))
+		}
+	}
+	flatten(decoded)
+
+	return list, nil
+}
+
+// decodeStringMap decodes an object into a map of strings, formatting non-string values.
+func decodeStringMap(value json.RawMessage) (map[string]string, error) {
+	var decoded map[string]any
+	if err := json.Unmarshal(value, &decoded); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(decoded))
+	for key, v := range decoded {
+		switch item := v.(type) {
+		case nil:
+			result[key] = ""
+		case string:
+			result[key] = item
+		default:
+			encoded, err := json.Marshal(item)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = string(encoded)
+		}
+	}
+	return result, nil
+}
+
+// decodeTables decodes the tables output into Table values.
+func decodeTables(value json.RawMessage) ([]Table, error) {
+	var wire []wireTable
+	if err := json.Unmarshal(value, &wire); err != nil {
+		return nil, err
+	}
+
+	tables := make([]Table, 0, len(wire))
+	for _, table := range wire {
+		decoded := Table{Columns: table.Heading, Rows: make([][]string, 0, len(table.Content))}
+		if len(decoded.Columns) == 0 && len(table.Content) > 0 {
+			// no heading: rows keyed by column name still name the columns
+			if cells, err := decodeStringMap(table.Content[0]); err == nil {
+				decoded.Columns = sortedKeys(cells)
+			}
+		}
+		for _, content := range table.Content {
+			row, err := decodeTableRow(content, decoded.Columns)
+			if err != nil {
+				return nil, err
+			}
+			decoded.Rows = append(decoded.Rows, row)
+		}
+		tables = append(tables, decoded)
+	}
+	return tables, nil
+}
+
+// decodeTableRow decodes a row keyed by column name into cells ordered like columns, or a row given as an array as-is.
+func decodeTableRow(content json.RawMessage, columns []string) ([]string, error) {
+	cells, err := decodeStringMap(content)
+	if err != nil {
+		return decodeStringList(content)
+	}
+
+	row := make([]string, 0, len(columns))
+	for _, column := range columns {
+		row = append(row, cells[column])
+	}
+	return row, nil
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,113 @@
+package scraperapi_test
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"testing"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+const outputsBody = `{
+	"emails": ["info@example.com"],
+	"phone_numbers": ["+1 555 0100"],
+	"headings": {"h1": ["Welcome"], "h2": ["About", "Contact"]},
+	"links": ["/about", "https://other.com/x", "contact"],
+	"images": ["/logo.png"],
+	"favicon": "/favicon.ico",
+	"metadata": {"title": "Example", "og:image:width": 1200},
+	"tables": [
+		{
+			"dimensions": {"rows": 2, "columns": 2, "heading": true},
+			"heading": ["Name", "Price"],
+			"content": [{"Price": "$1", "Name": "Apple"}, {"Name": "Pear, green", "Price": "$2"}]
+		}
+	]
+}`
+
+func TestResponseOutputsDecodesEveryOutputType(t *testing.T) {
+	params := &scraperapi.RequestParameters{Outputs: []scraperapi.OutputType{
+		scraperapi.OutputTypeEmails, scraperapi.OutputTypeLinks, scraperapi.OutputTypeTables, scraperapi.OutputTypeHashtags,
+	}}
+	res := doGetWithParams(t, params, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(outputsBody))
+	})
+
+	outputs, err := res.Outputs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !slices.Equal(outputs.Emails, []string{"info@example.com"}) || !slices.Equal(outputs.PhoneNumbers, []string{"+1 555 0100"}) {
+		t.Fatalf("unexpected emails/phone numbers: %v %v", outputs.Emails, outputs.PhoneNumbers)
+	}
+	if !slices.Equal(outputs.Headings, []string{"Welcome", "About", "Contact"}) {
+		t.Fatalf("unexpected headings: %v", outputs.Headings)
+	}
+	wantLinks := []string{"https://example.com/about", "https://other.com/x", "https://example.com/contact"}
+	if !slices.Equal(outputs.Links, wantLinks) {
+		t.Fatalf("expected links resolved against the target url, got %v", outputs.Links)
+	}
+	if outputs.Favicon != "https://example.com/favicon.ico" || outputs.Images[0] != "https://example.com/logo.png" {
+		t.Fatalf("unexpected favicon/images: %q %v", outputs.Favicon, outputs.Images)
+	}
+	if outputs.Metadata["title"] != "Example" || outputs.Metadata["og:image:width"] != "1200" {
+		t.Fatalf("unexpected metadata: %v", outputs.Metadata)
+	}
+	if !slices.Equal(outputs.Missing, []scraperapi.OutputType{scraperapi.OutputTypeHashtags}) {
+		t.Fatalf("expected hashtags to be reported missing, got %v", outputs.Missing)
+	}
+}
+
+func TestTableCSVExport(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(outputsBody)) })
+	outputs, err := res.Outputs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outputs.Tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(outputs.Tables))
+	}
+
+	table := outputs.Tables[0]
+	if !slices.Equal(table.Rows[0], []string{"Apple", "$1"}) {
+		t.Fatalf("expected cells ordered like the heading, got %v", table.Rows[0])
+	}
+
+	csv, err := table.CSV()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Name,Price\nApple,$1\n\"Pear, green\",$2\n"
+	if string(csv) != want {
+		t.Fatalf("got %q, want %q", csv, want)
+	}
+}
+
+func TestResponseOutputsReportsEveryMissingTypeForAll(t *testing.T) {
+	params := &scraperapi.RequestParameters{Outputs: []scraperapi.OutputType{scraperapi.OutputTypeAll}}
+	res := doGetWithParams(t, params, func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(`{"emails":[]}`)) })
+
+	outputs, err := res.Outputs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outputs.Missing) != len(scraperapi.AllOutputTypes)-2 || slices.Contains(outputs.Missing, scraperapi.OutputTypeEmails) {
+		t.Fatalf("unexpected missing outputs: %v", outputs.Missing)
+	}
+}
+
+func TestResponseOutputsRejectsErrorResponses(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"title":"Bad Request","status":400}`))
+	})
+
+	var unexpected scraperapi.UnexpectedContentError
+	if _, err := res.Outputs(); !errors.As(err, &unexpected) {
+		t.Fatalf("expected UnexpectedContentError, got %v", err)
+	}
+}
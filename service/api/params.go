@@ -155,7 +155,10 @@ type RequestParameters struct {
 	Mode Mode `json:"mode,omitempty" structs:"mode,omitempty" schema:"mode"`
 
 	// Output modifiers
-	AutoParse    bool   `json:"autoparse,omitempty" structs:"autoparse,omitempty" schema:"autoparse"`
+	AutoParse bool `json:"autoparse,omitempty" structs:"autoparse,omitempty" schema:"autoparse"`
+
+	// CSSExtractor is a serialized JSON object mapping result keys to CSS selectors. Build it with CSSExtractor() or
+	// CSSExtractorFor, and decode the result with DecodeCSSExtraction.
 	CSSExtractor string `json:"css_extractor,omitempty" structs:"css_extractor,omitempty" schema:"css_extractor"`
 
 	// Extract runs the request through Extract, ZenRows' AI-powered structured extraction
@@ -188,7 +191,8 @@ type RequestParameters struct {
 	JSRender bool `json:"js_render,omitempty" structs:"js_render,omitempty" schema:"js_render"`
 
 	// JSInstructions is a serialized JSON object that contains custom JavaScript instructions that will be executed in the page before
-	// returning the response (only available when using JSRender).
+	// returning the response (only available when using JSRender). Build it with JSInstructions() rather than by hand; Validate checks
+	// it against the known instruction set either way.
 	//
 	// See https://docs.zenrows.com/scraper-api/features/js-rendering#using-the-javascript-instructions for more information.
 	JSInstructions string `json:"js_instructions,omitempty" structs:"js_instructions,omitempty" schema:"js_instructions"`
@@ -341,6 +345,12 @@ func (p *RequestParameters) Validate() error { //nolint:gocyclo
 		}
 	}
 
+	if p.JSInstructions != "" {
+		if _, err := ParseJSInstructions(p.JSInstructions); err != nil {
+			return err
+		}
+	}
+
 	if !p.Screenshot {
 		if p.ScreenshotFullPage {
 			return InvalidParameterError{Msg: "screenshot_fullpage is only available when screenshot parameter is set to true"}
@@ -0,0 +1,163 @@
+package problem
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Code is an application-specific error code of the ZenRows Fetch API, as set in Problem.Code.
+//
+// See https://docs.zenrows.com/api-error-codes for more information.
+type Code string
+
+// Known problem codes of the ZenRows Fetch API. Codes missing from the catalog are still valid: they round-trip in Problem.Code,
+// and their category is CategoryUnknown.
+const (
+	CodeAPIKeyMissing       Code = "AUTH001"
+	CodeInvalidAPIKey       Code = "AUTH002"
+	CodeUsageExceeded       Code = "AUTH003"
+	CodeNoCreditAvailable   Code = "AUTH004"
+	CodeAPIKeyRevoked       Code = "AUTH005"
+	CodeConcurrencyExceeded Code = "AUTH006"
+	CodeDomainNotEnabled    Code = "AUTH010"
+	CodeURLNotAllowed       Code = "REQS001"
+	CodeEscalationRequired  Code = "REQS002"
+	CodeInvalidParameters   Code = "REQS004"
+	CodeCouldNotGetContent  Code = "RESP001"
+	CodeIPBlocked           Code = "BLK0001"
+	CodeContextCanceled     Code = "CTX0001"
+	CodeOperationTimeout    Code = "CTX0002"
+	CodeUnknownError        Code = "ERR0001"
+)
+
+// Category groups problem codes by what caused the failure.
+type Category string
+
+const (
+	// CategoryAuth is for failures authenticating the API key.
+	CategoryAuth Category = "auth"
+	// CategoryBilling is for failures caused by the plan: usage exceeded, no credits left, or a feature not enabled.
+	CategoryBilling Category = "billing"
+	// CategoryRequestValidation is for invalid requests: invalid parameters, or forbidden target URLs.
+	CategoryRequestValidation Category = "request_validation"
+	// CategoryTargetBlocked is for requests the target website blocked, or did not return content for, including those it only
+	// answers with JS rendering or premium proxies (REQS002).
+	CategoryTargetBlocked Category = "target_blocked"
+	// CategoryRateLimit is for requests over the concurrency or rate limits of the plan.
+	CategoryRateLimit Category = "rate_limit"
+	// CategoryTransient is for timeouts and internal errors of the ZenRows Fetch API.
+	CategoryTransient Category = "transient"
+	// CategoryUnknown is the category of the codes missing from the catalog.
+	CategoryUnknown Category = "unknown"
+)
+
+// codeInfo is what the catalog knows about a problem code.
+type codeInfo struct {
+	category  Category
+	retryable bool
+	title     string
+}
+
+// catalog is the catalog of known problem codes.
+var catalog = map[Code]codeInfo{
+	CodeAPIKeyMissing:       {CategoryAuth, false, "API key missing"},
+	CodeInvalidAPIKey:       {CategoryAuth, false, "invalid API key"},
+	CodeAPIKeyRevoked:       {CategoryAuth, false, "API key no longer valid"},
+	CodeUsageExceeded:       {CategoryBilling, false, "usage exceeded"},
+	CodeNoCreditAvailable:   {CategoryBilling, false, "no credit available"},
+	CodeDomainNotEnabled:    {CategoryBilling, false, "domain not enabled"},
+	CodeURLNotAllowed:       {CategoryRequestValidation, false, "requests to this URL are not allowed"},
+	CodeInvalidParameters:   {CategoryRequestValidation, false, "invalid parameters"},
+	CodeCouldNotGetContent:  {CategoryTargetBlocked, true, "could not get content"},
+	CodeIPBlocked:           {CategoryTargetBlocked, true, "IP address blocked"},
+	CodeEscalationRequired:  {CategoryTargetBlocked, false, "target requires JS rendering or premium proxies"},
+	CodeConcurrencyExceeded: {CategoryRateLimit, true, "concurrency exceeded"},
+	CodeContextCanceled:     {CategoryTransient, true, "context canceled"},
+	CodeOperationTimeout:    {CategoryTransient, true, "operation timeout"},
+	CodeUnknownError:        {CategoryTransient, true, "unknown error"},
+}
+
+// Normalize returns the code in upper case, without surrounding spaces, as listed in the catalog.
+func (c Code) Normalize() Code {
+	return Code(strings.ToUpper(strings.TrimSpace(string(c))))
+}
+
+// Known reports whether the code is in the catalog.
+func (c Code) Known() bool {
+	_, ok := catalog[c.Normalize()]
+	return ok
+}
+
+// Category returns the category of the code, or CategoryUnknown if it is not in the catalog.
+func (c Code) Category() Category {
+	if info, ok := catalog[c.Normalize()]; ok {
+		return info.category
+	}
+	return CategoryUnknown
+}
+
+// Retryable reports whether retrying a request that failed with the code can succeed. It is false for codes not in the catalog.
+func (c Code) Retryable() bool {
+	return catalog[c.Normalize()].retryable
+}
+
+// ProblemCode returns the code of the problem, as a Code.
+func (p *Problem) ProblemCode() Code {
+	return Code(p.Code)
+}
+
+// Category returns the category of the problem code, or CategoryUnknown if it is not in the catalog.
+func (p *Problem) Category() Category {
+	return p.ProblemCode().Category()
+}
+
+// Retryable reports whether retrying the request can succeed. For codes not in the catalog, it tells by the status code: 429 Too
+// Many Requests and 5xx are retryable.
+func (p *Problem) Retryable() bool {
+	if p.ProblemCode().Known() {
+		return p.ProblemCode().Retryable()
+	}
+	return p.Status == http.StatusTooManyRequests || p.Status >= http.StatusInternalServerError
+}
+
+// Is reports whether the problem matches target, so errors.Is works with the sentinel errors of the catalog:
+//
+//	if errors.Is(response.Error(), problem.ErrDomainNotEnabled) { ... }
+func (p *Problem) Is(target error) bool {
+	if p == nil {
+		return false
+	}
+	sentinel, ok := target.(*CodeError)
+	return ok && sentinel.Code == p.ProblemCode().Normalize()
+}
+
+// CodeError is a sentinel error matching, with errors.Is, every Problem with its code.
+type CodeError struct {
+	Code Code
+}
+
+func (e *CodeError) Error() string {
+	if info, ok := catalog[e.Code]; ok {
+		return "zenrows: " + info.title + " (" + string(e.Code) + ")"
+	}
+	return "zenrows: problem " + string(e.Code)
+}
+
+// Sentinel errors of the known problem codes, to match problems with errors.Is.
+var (
+	ErrAPIKeyMissing       = &CodeError{Code: CodeAPIKeyMissing}
+	ErrInvalidAPIKey       = &CodeError{Code: CodeInvalidAPIKey}
+	ErrAPIKeyRevoked       = &CodeError{Code: CodeAPIKeyRevoked}
+	ErrUsageExceeded       = &CodeError{Code: CodeUsageExceeded}
+	ErrNoCreditAvailable   = &CodeError{Code: CodeNoCreditAvailable}
+	ErrDomainNotEnabled    = &CodeError{Code: CodeDomainNotEnabled}
+	ErrURLNotAllowed       = &CodeError{Code: CodeURLNotAllowed}
+	ErrEscalationRequired  = &CodeError{Code: CodeEscalationRequired}
+	ErrInvalidParameters   = &CodeError{Code: CodeInvalidParameters}
+	ErrCouldNotGetContent  = &CodeError{Code: CodeCouldNotGetContent}
+	ErrIPBlocked           = &CodeError{Code: CodeIPBlocked}
+	ErrConcurrencyExceeded = &CodeError{Code: CodeConcurrencyExceeded}
+	ErrContextCanceled     = &CodeError{Code: CodeContextCanceled}
+	ErrOperationTimeout    = &CodeError{Code: CodeOperationTimeout}
+	ErrUnknownError        = &CodeError{Code: CodeUnknownError}
+)
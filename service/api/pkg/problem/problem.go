@@ -1,6 +1,9 @@
 package problem
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 const (
 	// ContentTypeJSON https://tools.ietf.org/html/rfc7807#section-6.1
@@ -30,6 +33,42 @@ type Problem struct { //nolint:errname
 	// Type is the type URI (typically, with the "http" or "https" scheme) that identifies the problem type.
 	// When dereferenced, it SHOULD provide human-readable documentation for the problem type
 	Type string `json:"type"`
+
+	// Extras holds the extension members of the problem, not modeled above.
+	Extras map[string]any `json:"-"`
+}
+
+// standardFields lists the members modeled on Problem itself; anything else in the body lands in Problem.Extras.
+var standardFields = map[string]bool{
+	"code": true, "detail": true, "instance": true, "status": true, "title": true, "type": true,
+}
+
+// Parse decodes a problem JSON body, keeping its extension members in Extras. It returns nil if the body is a JSON null.
+func Parse(body []byte) (*Problem, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil || raw == nil {
+		return nil, err
+	}
+
+	var p Problem
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, err
+	}
+
+	for k, v := range raw {
+		if standardFields[k] {
+			continue
+		}
+		var value any
+		if err := json.Unmarshal(v, &value); err != nil {
+			return nil, err
+		}
+		if p.Extras == nil {
+			p.Extras = make(map[string]any)
+		}
+		p.Extras[k] = value
+	}
+	return &p, nil
 }
 
 func (p *Problem) Error() string {
@@ -1,6 +1,10 @@
 package problem_test
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
@@ -25,3 +29,54 @@ func TestErrorWithoutDetail(t *testing.T) {
 		t.Fatalf("got %q, want %q", got, want)
 	}
 }
+
+func TestCatalogCategoriesAndRetryable(t *testing.T) {
+	tests := []struct {
+		code      string
+		category  problem.Category
+		retryable bool
+	}{
+		{"AUTH002", problem.CategoryAuth, false},
+		{"auth010", problem.CategoryBilling, false},
+		{"REQS004", problem.CategoryRequestValidation, false},
+		{"RESP001", problem.CategoryTargetBlocked, true},
+		{"reqs002", problem.CategoryTargetBlocked, false},
+		{"AUTH006", problem.CategoryRateLimit, true},
+		{"CTX0002", problem.CategoryTransient, true},
+		{"NEW0001", problem.CategoryUnknown, false},
+	}
+	for _, tt := range tests {
+		p := &problem.Problem{Code: tt.code, Status: 400}
+		if got := p.Category(); got != tt.category {
+			t.Errorf("%s: got category %q, want %q", tt.code, got, tt.category)
+		}
+		if got := p.Retryable(); got != tt.retryable {
+			t.Errorf("%s: got retryable %v, want %v", tt.code, got, tt.retryable)
+		}
+	}
+}
+
+func TestUnknownCodeRoundTrips(t *testing.T) {
+	var p problem.Problem
+	if err := json.Unmarshal([]byte(`{"code":"NEW0001","status":503}`), &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Code != "NEW0001" || p.ProblemCode().Known() || !p.Retryable() {
+		t.Fatalf("expected the unknown code to round-trip and be retryable by status, got %+v", p)
+	}
+	data, err := json.Marshal(p)
+	if err != nil || !strings.Contains(string(data), `"code":"NEW0001"`) {
+		t.Fatalf("expected the unknown code to be marshaled back, got %s (%v)", data, err)
+	}
+}
+
+func TestErrorsIsMatchesSentinels(t *testing.T) {
+	var err error = fmt.Errorf("scrape: %w", &problem.Problem{Code: "auth010", Status: 402})
+
+	if !errors.Is(err, problem.ErrDomainNotEnabled) {
+		t.Fatalf("expected the problem to match ErrDomainNotEnabled")
+	}
+	if errors.Is(err, problem.ErrInvalidAPIKey) {
+		t.Fatalf("expected the problem not to match ErrInvalidAPIKey")
+	}
+}
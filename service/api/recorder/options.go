@@ -0,0 +1,78 @@
+package recorder
+
+import "net/http"
+
+// Option configures the Recorder.
+type Option interface {
+	apply(*options)
+}
+
+// options holds the configuration for the Recorder
+type options struct {
+	// transport sends the requests while recording. Defaults to http.DefaultTransport.
+	transport http.RoundTripper
+	// matching is how requests are matched with recorded interactions while replaying. Defaults to MatchStrict.
+	matching Matching
+	// redactedParams are the query parameters whose values are redacted from fixtures. Defaults to apikey and the signature
+	// parameters of presigned storage URLs.
+	redactedParams []string
+	// redactedHeaders are the request headers whose values are redacted from fixtures. Defaults to X-API-Key and Authorization.
+	redactedHeaders []string
+}
+
+// defaultOptions returns the default options for the Recorder.
+func defaultOptions() options {
+	return options{
+		transport:       http.DefaultTransport,
+		matching:        MatchStrict,
+		redactedParams:  append([]string{"apikey"}, presignedSecretParams...),
+		redactedHeaders: []string{"X-API-Key", "Authorization"},
+	}
+}
+
+// funcOption wraps a function that modifies options into an implementation of the Option interface.
+type funcOption struct {
+	f func(*options)
+}
+
+func (fo *funcOption) apply(o *options) {
+	fo.f(o)
+}
+
+func newFuncOption(f func(*options)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+// WithTransport returns an Option which configures the transport that sends the requests while recording. Defaults to
+// http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return newFuncOption(func(o *options) {
+		o.transport = transport
+	})
+}
+
+// WithMatching returns an Option which configures how requests are matched with the recorded interactions while replaying.
+// Defaults to MatchStrict.
+func WithMatching(matching Matching) Option {
+	return newFuncOption(func(o *options) {
+		o.matching = matching
+	})
+}
+
+// WithRedactedParams returns an Option which adds query parameters whose values are redacted from fixtures, and ignored when
+// matching requests. The apikey parameter and the signature parameters of presigned storage URLs are always redacted.
+func WithRedactedParams(names ...string) Option {
+	return newFuncOption(func(o *options) {
+		o.redactedParams = append(o.redactedParams, names...)
+	})
+}
+
+// WithRedactedHeaders returns an Option which adds request headers whose values are redacted from fixtures. The X-API-Key and
+// Authorization headers are always redacted.
+func WithRedactedHeaders(names ...string) Option {
+	return newFuncOption(func(o *options) {
+		o.redactedHeaders = append(o.redactedHeaders, names...)
+	})
+}
@@ -0,0 +1,413 @@
+// Package recorder records the interactions of a client with the ZenRows APIs to fixture files, and replays them, so tests run
+// offline and deterministically. API keys are redacted from the fixtures, so they can be committed.
+//
+// Plug a Recorder in as the transport of a client, and stop it at the end of the test to write the fixture:
+//
+//	rec, err := recorder.New("testdata/fixtures/get.json", recorder.ModeAuto)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//
+//	client := scraperapi.NewClient(scraperapi.WithHTTPClient(rec.Client()))
+//
+// It only depends on the standard library, so it records the Batch API client too, along with its downloads and uploads to
+// presigned storage URLs, whose signatures are redacted:
+//
+//	client := batch.NewClient(batch.WithHTTPClient(rec.Client()))
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// redactedValue replaces the secrets in fixtures.
+const redactedValue = "[REDACTED]"
+
+// presignedSecretParams are the query parameters signing presigned storage URLs (S3 and GCS), redacted from fixtures and ignored
+// when matching requests.
+var presignedSecretParams = []string{
+	"X-Amz-Signature", "X-Amz-Credential", "X-Amz-Security-Token",
+	"X-Goog-Signature", "X-Goog-Credential",
+	"Signature", "AWSAccessKeyId",
+}
+
+// presignedVolatileParams are the query parameters of presigned storage URLs that change every time a URL is signed. They are
+// ignored when matching requests, but are not secrets, so their values are only redacted from URLs.
+var presignedVolatileParams = []string{"X-Amz-Date", "X-Amz-Expires", "X-Goog-Date", "X-Goog-Expires", "Expires"}
+
+// Mode tells whether a Recorder records or replays interactions.
+type Mode int
+
+const (
+	// ModeReplay replays the interactions of the fixture, which must exist, and never sends requests.
+	ModeReplay Mode = iota
+	// ModeRecord sends every request and records it, overwriting the fixture on Recorder.Stop.
+	ModeRecord
+	// ModeAuto replays the fixture if it exists, and records it otherwise.
+	ModeAuto
+)
+
+// Matching tells how a Recorder matches requests with the recorded interactions while replaying. Requests are always matched by
+// method, path, and query parameters, sorted and without the redacted ones and the expiry of presigned URLs; the host is ignored,
+// so fixtures recorded against a base URL replay against any other.
+type Matching int
+
+const (
+	// MatchStrict replays the interactions in the order they were recorded: every request must match the next interaction, body
+	// included.
+	MatchStrict Matching = iota
+	// MatchLenient replays the first interaction matching the request that was not replayed yet, whatever the order of the requests,
+	// and ignores bodies. Once every matching interaction was replayed, the last one is replayed again, e.g. for polls.
+	MatchLenient
+)
+
+// Interaction is a request and the response it got, as stored in a fixture.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request stored in a fixture, with its secrets redacted.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response stored in a fixture. Text bodies are stored in Body, and binary ones in BodyBase64.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 []byte      `json:"body_base64,omitempty"`
+}
+
+// body returns the body of the response.
+func (r RecordedResponse) body() []byte {
+	if r.BodyBase64 != nil {
+		return r.BodyBase64
+	}
+	return []byte(r.Body)
+}
+
+// fixture is the content of a fixture file.
+type fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// NoMatchError is returned, while replaying, for requests that match no recorded interaction.
+type NoMatchError struct {
+	Method string
+	URL    string
+}
+
+func (e NoMatchError) Error() string {
+	return "recorder: no recorded interaction matches " + e.Method + " " + e.URL
+}
+
+// Recorder is an http.RoundTripper that records interactions to a fixture file, or replays them from it. It is safe for concurrent
+// use, although only MatchLenient replays concurrent requests deterministically.
+type Recorder struct {
+	cfg  options
+	path string
+	mode Mode
+
+	mu           sync.Mutex
+	interactions []Interaction
+	replayed     []bool
+	next         int
+	secrets      []string
+}
+
+// New creates and returns a new Recorder for the fixture at path, in the given mode. In ModeReplay, the fixture must exist.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{cfg: defaultOptions(), path: path, mode: mode}
+	for _, opt := range opts {
+		opt.apply(&r.cfg)
+	}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeReplay
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			r.mode = ModeRecord
+		}
+	}
+	if r.mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f fixture
+	if err = json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	r.interactions = f.Interactions
+	r.replayed = make([]bool, len(f.Interactions))
+	return r, nil
+}
+
+// Mode returns whether the recorder records or replays interactions: ModeRecord or ModeReplay, never ModeAuto.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an http.Client whose requests go through the recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns a copy of the interactions recorded or loaded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.interactions)
+}
+
+// Stop writes the recorded interactions to the fixture, creating its directory if needed. It does nothing while replaying.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	// secrets seen after an interaction was recorded, e.g. the signature of a presigned URL returned in an earlier response body,
+	// are redacted from it too
+	for i := range r.interactions {
+		r.interactions[i] = r.redactInteraction(r.interactions[i])
+	}
+	data, err := json.MarshalIndent(fixture{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o600)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+// record sends the request, and records it along with its response.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	r.addSecrets(req)
+	r.mu.Unlock()
+
+	res, err := r.cfg.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	recorded := RecordedResponse{StatusCode: res.StatusCode, Status: res.Status, Header: r.redactHeader(res.Header, nil)}
+	if utf8.Valid(resBody) {
+		recorded.Body = r.redact(string(resBody))
+	} else {
+		recorded.BodyBase64 = resBody
+	}
+	r.interactions = append(r.interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    r.redactURL(req.URL),
+			Header: r.redactHeader(req.Header, r.cfg.redactedHeaders),
+			Body:   r.redact(string(body)),
+		},
+		Response: recorded,
+	})
+	return res, nil
+}
+
+// replay returns the response of the recorded interaction matching the request.
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the secrets of the request are redacted from the recorded bodies and URLs it is compared with
+	r.addSecrets(req)
+
+	key := r.matchKey(req.Method, req.URL)
+	index := -1
+	switch r.cfg.matching {
+	case MatchStrict:
+		if r.next < len(r.interactions) && r.interactionKey(r.interactions[r.next]) == key &&
+			r.interactions[r.next].Request.Body == r.redact(string(body)) {
+			index = r.next
+			r.next++
+		}
+	case MatchLenient:
+		for i, interaction := range r.interactions {
+			if r.interactionKey(interaction) != key {
+				continue
+			}
+			index = i
+			if !r.replayed[i] {
+				break
+			}
+		}
+	}
+	if index < 0 {
+		return nil, NoMatchError{Method: req.Method, URL: r.redactURL(req.URL)}
+	}
+	r.replayed[index] = true
+
+	recorded := r.interactions[index].Response
+	header := recorded.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	resBody := recorded.body()
+	return &http.Response{
+		Status:        recorded.Status,
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(resBody)),
+		ContentLength: int64(len(resBody)),
+		Request:       req,
+	}, nil
+}
+
+// readRequestBody reads the body of the request, and replaces it with a copy so it can still be sent.
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// interactionKey returns the match key of a recorded interaction.
+func (r *Recorder) interactionKey(interaction Interaction) string {
+	u, err := url.Parse(interaction.Request.URL)
+	if err != nil {
+		return ""
+	}
+	return r.matchKey(interaction.Request.Method, u)
+}
+
+// matchKey returns what requests are matched by: their method, path, and sorted query parameters, without the redacted and volatile
+// ones.
+func (r *Recorder) matchKey(method string, u *url.URL) string {
+	query := u.Query()
+	for _, name := range slices.Concat(r.cfg.redactedParams, presignedVolatileParams) {
+		query.Del(name)
+	}
+	return strings.ToUpper(method) + " " + u.EscapedPath() + "?" + query.Encode()
+}
+
+// addSecrets remembers the values of the redacted query parameters and headers of the request, to redact them wherever they appear.
+func (r *Recorder) addSecrets(req *http.Request) {
+	var values []string
+	query := req.URL.Query()
+	for _, name := range r.cfg.redactedParams {
+		values = append(values, query[name]...)
+	}
+	for _, name := range r.cfg.redactedHeaders {
+		values = append(values, req.Header.Values(name)...)
+	}
+	for _, value := range values {
+		// secrets are also redacted where they appear escaped, e.g. in a URL returned in a response body
+		for _, secret := range []string{value, url.QueryEscape(value)} {
+			if secret != "" && !slices.Contains(r.secrets, secret) {
+				r.secrets = append(r.secrets, secret)
+			}
+		}
+	}
+}
+
+// redactInteraction returns the interaction with the secrets seen so far redacted.
+func (r *Recorder) redactInteraction(interaction Interaction) Interaction {
+	interaction.Request.URL = r.redact(interaction.Request.URL)
+	interaction.Request.Header = r.redactHeader(interaction.Request.Header, nil)
+	interaction.Request.Body = r.redact(interaction.Request.Body)
+	interaction.Response.Header = r.redactHeader(interaction.Response.Header, nil)
+	interaction.Response.Body = r.redact(interaction.Response.Body)
+	return interaction
+}
+
+// redact replaces the secrets seen so far in s.
+func (r *Recorder) redact(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	return s
+}
+
+// redactURL returns the URL with the values of the redacted query parameters, and any other secret, redacted.
+func (r *Recorder) redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	for _, name := range slices.Concat(r.cfg.redactedParams, presignedVolatileParams) {
+		if query.Has(name) {
+			query.Set(name, redactedValue)
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return r.redact(redacted.String())
+}
+
+// redactHeader returns a copy of header with the values of the given headers, and any other secret, redacted.
+func (r *Recorder) redactHeader(header http.Header, names []string) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+
+	redacted := make(http.Header, len(header))
+	for key, values := range header {
+		if slices.ContainsFunc(names, func(name string) bool { return strings.EqualFold(name, key) }) {
+			redacted[key] = []string{redactedValue}
+			continue
+		}
+		for _, v := range values {
+			redacted[key] = append(redacted[key], r.redact(v))
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,188 @@
+package recorder_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/recorder"
+)
+
+const apiKey = "s3cr3t-api-key"
+
+// record records a GET request for each target URL to a new fixture, and returns its path.
+func record(t *testing.T, targets ...string) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<p>" + r.URL.Query().Get("url") + " for " + r.URL.Query().Get("apikey") + "</p>"))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures", "get.json")
+	rec, err := recorder.New(path, recorder.ModeAuto)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Mode() != recorder.ModeRecord {
+		t.Fatalf("expected a missing fixture to be recorded, got mode %d", rec.Mode())
+	}
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey(apiKey), scraperapi.WithHTTPClient(rec.Client()))
+	for _, target := range targets {
+		if _, err = client.Get(context.Background(), target, &scraperapi.RequestParameters{JSRender: true}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err = rec.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return path
+}
+
+// replayClient returns a client replaying the fixture at path, against a base URL nothing listens on.
+func replayClient(t *testing.T, path string, opts ...recorder.Option) *scraperapi.Client {
+	t.Helper()
+
+	rec, err := recorder.New(path, recorder.ModeReplay, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return scraperapi.NewClient(
+		scraperapi.WithBaseURL("http://127.0.0.1:1"),
+		scraperapi.WithAPIKey("another-api-key"),
+		scraperapi.WithHTTPClient(rec.Client()),
+	)
+}
+
+func TestRecorderRedactsTheAPIKeyFromFixtures(t *testing.T) {
+	path := record(t, "https://example.com")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), apiKey) {
+		t.Fatalf("expected the API key to be redacted, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), "[REDACTED]") {
+		t.Fatalf("expected redacted values in the fixture, got:\n%s", data)
+	}
+}
+
+func TestRecorderReplaysInStrictOrder(t *testing.T) {
+	path := record(t, "https://example.com/a", "https://example.com/b")
+	client := replayClient(t, path)
+
+	res, err := client.Get(context.Background(), "https://example.com/a", &scraperapi.RequestParameters{JSRender: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.String() != "<p>https://example.com/a for [REDACTED]</p>" {
+		t.Fatalf("unexpected body %q", res.String())
+	}
+
+	// the next recorded request is for /b, so /a again does not match
+	_, err = client.Get(context.Background(), "https://example.com/a", &scraperapi.RequestParameters{JSRender: true})
+	var noMatch recorder.NoMatchError
+	if !errors.As(err, &noMatch) {
+		t.Fatalf("expected a NoMatchError, got %v", err)
+	}
+	if strings.Contains(noMatch.URL, "another-api-key") {
+		t.Fatalf("expected the API key to be redacted from the error, got %q", noMatch.URL)
+	}
+}
+
+func TestRecorderReplaysInAnyOrderWhenLenient(t *testing.T) {
+	path := record(t, "https://example.com/a", "https://example.com/b")
+	client := replayClient(t, path, recorder.WithMatching(recorder.MatchLenient))
+
+	for _, target := range []string{"https://example.com/b", "https://example.com/a", "https://example.com/a"} {
+		res, err := client.Get(context.Background(), target, &scraperapi.RequestParameters{JSRender: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(res.String(), "<p>"+target+" ") {
+			t.Fatalf("expected the response recorded for %s, got %q", target, res.String())
+		}
+	}
+
+	// query parameters are part of the match
+	if _, err := client.Get(context.Background(), "https://example.com/a", nil); err == nil {
+		t.Fatal("expected a request with other parameters not to match")
+	}
+}
+
+func TestRecorderRecordsAndReplaysPresignedDownloads(t *testing.T) {
+	const signature = "d3adb33f-signature"
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/storage/") {
+			_, _ = w.Write([]byte("body of " + strings.TrimPrefix(r.URL.Path, "/storage/")))
+			return
+		}
+		// every listing signs a new URL, as the real storage does
+		_, _ = w.Write([]byte(server.URL + "/storage/task_1?" + url.Values{
+			"X-Amz-Credential": {"AKIAEXAMPLE/20260101/us-east-1/s3/aws4_request"},
+			"X-Amz-Date":       {time.Now().Format("20060102T150405.000000000Z")},
+			"X-Amz-Signature":  {signature},
+		}.Encode()))
+	}))
+	defer server.Close()
+
+	// download lists the presigned URL of the result of a task, the way the Batch API client does, and downloads it
+	download := func(client *http.Client, baseURL string) string {
+		t.Helper()
+		get := func(rawURL string) string {
+			res, err := client.Get(rawURL)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return string(body)
+		}
+		return get(get(baseURL + "/jobs/job_1/results?apikey=" + apiKey))
+	}
+
+	path := filepath.Join(t.TempDir(), "download.json")
+	rec, err := recorder.New(path, recorder.ModeRecord)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body := download(rec.Client(), server.URL); body != "body of task_1" {
+		t.Fatalf("unexpected body %q", body)
+	}
+	if err = rec.Stop(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), signature) || strings.Contains(string(data), "AKIAEXAMPLE") {
+		t.Fatalf("expected the presigned URL signature to be redacted, got:\n%s", data)
+	}
+
+	replay, err := recorder.New(path, recorder.ModeReplay)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body := download(replay.Client(), "http://127.0.0.1:1"); body != "body of task_1" {
+		t.Fatalf("unexpected replayed body %q", body)
+	}
+}
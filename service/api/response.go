@@ -1,8 +1,10 @@
 package scraperapi
 
 import (
-	"encoding/json"
+	"bytes"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,9 +18,43 @@ type Response struct {
 	RawResponse *http.Response
 
 	res *resty.Response
+
+	// targetURL and params are the target URL and request parameters the response was requested with, if any. Helpers that depend
+	// on what was asked for (e.g. the requested screenshot format) read them.
+	targetURL string
+	params    *RequestParameters
+
+	// extractSource records which request produced the response when it comes from Client.Extract.
+	extractSource ExtractSource
+	// stream is the unread body of a response returned by Client.ScrapeStream.
+	stream *streamBody
+	// cachedAt is when the response was stored in the cache of the client, if it was served from it (see WithCache).
+	cachedAt time.Time
+}
+
+// NewResponse creates and returns a Response with the given status code, headers and body, as if the ZenRows Fetch API had sent
+// it, e.g. to return from a fake IClient in tests.
+func NewResponse(statusCode int, header http.Header, body []byte) *Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	raw := &http.Response{
+		Status:     strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		StatusCode: statusCode,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+	return &Response{res: (&resty.Response{Request: &resty.Request{}, RawResponse: raw}).SetBody(body)}
+}
+
+// FromCache method returns true if the response was served from the cache of the client (see WithCache), without sending the
+// request. Its Cost is what the original request was charged, not what this one was: cached responses are free.
+func (r *Response) FromCache() bool {
+	return !r.cachedAt.IsZero()
 }
 
-// Body method returns the HTTP response as `[]byte` slice for the executed request.
+// Body method returns the HTTP response as `[]byte` slice for the executed request. It is empty for successful responses returned
+// by Client.ScrapeStream; read those from [Response.BodyReader].
 func (r *Response) Body() []byte {
 	return r.res.Body()
 }
@@ -48,11 +84,11 @@ func (r *Response) String() string {
 	return r.res.String()
 }
 
-// Problem method returns the problem description of the HTTP response if any.
+// Problem method returns the problem description of the HTTP response if any. The content type of the response must be
+// problem.ContentTypeJSON, with or without parameters such as the charset.
 func (r *Response) Problem() *problem.Problem {
-	if r.IsError() && r.Header().Get("Content-Type") == problem.ContentTypeJSON {
-		var prob *problem.Problem
-		if err := json.Unmarshal(r.Body(), &prob); err == nil {
+	if r.IsError() && r.mediaType() == problem.ContentTypeJSON {
+		if prob, err := problem.Parse(r.Body()); err == nil {
 			return prob
 		}
 	}
@@ -60,30 +96,46 @@ func (r *Response) Problem() *problem.Problem {
 	return nil
 }
 
-// Error method returns the error message of the HTTP response if any.
+// Error method returns an HTTPError if the HTTP response is an error (see Response.IsError), or nil otherwise. The HTTPError wraps
+// the problem description of the response, if any, so errors.As and errors.Is work with problem.Problem and its sentinel errors.
 func (r *Response) Error() error {
-	if prob := r.Problem(); prob != nil {
-		return prob
+	if !r.IsError() {
+		return nil
 	}
 
-	return nil
+	return HTTPError{
+		StatusCode: r.StatusCode(),
+		Status:     r.Status(),
+		Body:       bodySnippet(r.Body()),
+		Problem:    r.Problem(),
+	}
 }
 
 // Time method returns the duration of HTTP response time from the request we sent
 // and received a request.
 //
-// See [Response.ReceivedAt] to know when the client received a response.
+// See [Response.ReceivedAt] to know when the client received a response. It is 0 for responses served from the cache.
 func (r *Response) Time() time.Duration {
+	if r.FromCache() {
+		return 0
+	}
 	return r.res.Time()
 }
 
-// ReceivedAt method returns the time we received a response from the server for the request.
+// ReceivedAt method returns the time we received a response from the server for the request. For responses served from the cache,
+// it is when the response was originally received.
 func (r *Response) ReceivedAt() time.Time {
+	if r.FromCache() {
+		return r.cachedAt
+	}
 	return r.res.ReceivedAt()
 }
 
 // Size method returns the HTTP response size in bytes.
 func (r *Response) Size() int64 {
+	if r.FromCache() {
+		return int64(len(r.Body()))
+	}
 	return r.res.Size()
 }
 
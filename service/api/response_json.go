@@ -0,0 +1,159 @@
+package scraperapi
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// JSONResponse is the envelope the ZenRows Fetch API returns when RequestParameters.JSONResponse is set: the rendered HTML plus
+// everything the headless browser captured along the way.
+//
+// See https://docs.zenrows.com/scraper-api/features/json-response for more information.
+type JSONResponse struct {
+	// HTML is the rendered HTML of the target page.
+	HTML string `json:"html"`
+
+	// XHR holds the XHR/fetch requests the page made while rendering, with their responses.
+	XHR XHRCaptures `json:"xhr,omitempty"`
+
+	// JSInstructionsReport describes how each of the RequestParameters.JSInstructions went, if any were sent.
+	JSInstructionsReport *JSInstructionsReport `json:"js_instructions_report,omitempty"`
+
+	// Screenshot holds the page screenshot, if RequestParameters.Screenshot was set.
+	Screenshot *EmbeddedScreenshot `json:"screenshot,omitempty"`
+}
+
+// XHRCapture is a single XHR/fetch request made by the page, together with the response it got.
+type XHRCapture struct {
+	URL            string            `json:"url"`
+	Method         string            `json:"method,omitempty"`
+	StatusCode     int               `json:"status_code,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Body           string            `json:"body,omitempty"`
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	RequestBody    string            `json:"request_body,omitempty"`
+}
+
+// Header returns the response headers of the captured request as an http.Header.
+func (c XHRCapture) Header() http.Header {
+	header := make(http.Header, len(c.Headers))
+	for k, v := range c.Headers {
+		header.Set(k, v)
+	}
+	return header
+}
+
+// DecodeBody unmarshals the captured response body, which for API calls made by SPAs is usually JSON, into v.
+func (c XHRCapture) DecodeBody(v any) error {
+	return json.Unmarshal([]byte(c.Body), v)
+}
+
+// XHRCaptures is the list of XHR/fetch requests captured while rendering a page.
+type XHRCaptures []XHRCapture
+
+// Filter returns the captures for which keep returns true, preserving their order.
+func (c XHRCaptures) Filter(keep func(XHRCapture) bool) XHRCaptures {
+	filtered := make(XHRCaptures, 0, len(c))
+	for _, capture := range c {
+		if keep(capture) {
+			filtered = append(filtered, capture)
+		}
+	}
+	return filtered
+}
+
+// ByMethod returns the captures made with any of the given HTTP methods (case-insensitive).
+func (c XHRCaptures) ByMethod(methods ...string) XHRCaptures {
+	return c.Filter(func(capture XHRCapture) bool {
+		for _, method := range methods {
+			if strings.EqualFold(capture.Method, method) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// ByURL returns the captures whose URL matches the given pattern.
+func (c XHRCaptures) ByURL(pattern *regexp.Regexp) XHRCaptures {
+	return c.Filter(func(capture XHRCapture) bool {
+		return pattern.MatchString(capture.URL)
+	})
+}
+
+// ByURLContains returns the captures whose URL contains the given substring, e.g. "/api/".
+func (c XHRCaptures) ByURLContains(substr string) XHRCaptures {
+	return c.Filter(func(capture XHRCapture) bool {
+		return strings.Contains(capture.URL, substr)
+	})
+}
+
+// JSInstructionsReport is the execution report of the RequestParameters.JSInstructions sent with the request.
+type JSInstructionsReport struct {
+	Instructions          []JSInstructionReport `json:"instructions"`
+	InstructionsDuration  int                   `json:"instructions_duration"` // milliseconds
+	InstructionsExecuted  int                   `json:"instructions_executed"`
+	InstructionsSucceeded int                   `json:"instructions_succeeded"`
+	InstructionsFailed    int                   `json:"instructions_failed"`
+}
+
+// Failed returns the reports of the instructions that did not succeed.
+func (r *JSInstructionsReport) Failed() []JSInstructionReport {
+	var failed []JSInstructionReport
+	for _, instruction := range r.Instructions {
+		if !instruction.Success {
+			failed = append(failed, instruction)
+		}
+	}
+	return failed
+}
+
+// JSInstructionReport is the outcome of a single JavaScript instruction.
+type JSInstructionReport struct {
+	Instruction JSInstructionAction `json:"instruction"`
+	Params      map[string]any      `json:"params,omitempty"`
+	Success     bool                `json:"success"`
+	Duration    int                 `json:"duration"` // milliseconds
+}
+
+// EmbeddedScreenshot is a base64-encoded screenshot embedded in a JSONResponse.
+type EmbeddedScreenshot struct {
+	// Data is the base64-encoded image.
+	Data string `json:"data"`
+	// Type is the MIME type of the image, e.g. "image/png".
+	Type   string `json:"type,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// Bytes returns the decoded image bytes.
+func (s *EmbeddedScreenshot) Bytes() ([]byte, error) {
+	data := s.Data
+	// tolerate data URLs, e.g. "data:image/png;base64,iVBOR..."
+	if idx := strings.Index(data, ";base64,"); strings.HasPrefix(data, "data:") && idx >= 0 {
+		data = data[idx+len(";base64,"):]
+	}
+	return base64.StdEncoding.DecodeString(data)
+}
+
+// JSON method decodes the body of a response sent with RequestParameters.JSONResponse set into a JSONResponse. It returns an
+// UnexpectedContentError if the body is not a JSON envelope, or if the response is streamed (see Client.ScrapeStream); when the
+// request failed, the error wraps the response problem.
+func (r *Response) JSON() (*JSONResponse, error) {
+	if r.IsError() {
+		return nil, r.unexpectedContent("json_response envelope")
+	}
+	if err := r.streamedContent("json_response envelope"); err != nil {
+		return nil, err
+	}
+
+	var envelope JSONResponse
+	if err := json.Unmarshal(r.Body(), &envelope); err != nil {
+		return nil, UnexpectedContentError{Expected: "json_response envelope", ContentType: r.Header().Get("Content-Type"), Err: err}
+	}
+
+	return &envelope, nil
+}
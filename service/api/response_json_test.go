@@ -0,0 +1,120 @@
+package scraperapi_test
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
+)
+
+const jsonResponseBody = `{
+	"html": "<html><body>hi</body></html>",
+	"xhr": [
+		{"url": "https://example.com/api/products?page=1", "method": "GET", "status_code": 200,
+		 "headers": {"content-type": "application/json"}, "body": "{\"items\":[1,2,3]}"},
+		{"url": "https://example.com/api/track", "method": "POST", "status_code": 204, "request_body": "{}"},
+		{"url": "https://cdn.example.com/config.json", "method": "GET", "status_code": 200, "body": "{}"}
+	],
+	"js_instructions_report": {
+		"instructions": [
+			{"instruction": "click", "params": {"selector": "#accept"}, "success": true, "duration": 41},
+			{"instruction": "wait_for", "params": {"selector": ".missing"}, "success": false, "duration": 10000}
+		],
+		"instructions_duration": 10041,
+		"instructions_executed": 2,
+		"instructions_succeeded": 1,
+		"instructions_failed": 1
+	},
+	"screenshot": {"data": "aGVsbG8=", "type": "image/png", "width": 1, "height": 1}
+}`
+
+func TestResponseJSONDecodesEnvelope(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(jsonResponseBody))
+	})
+
+	envelope, err := res.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if envelope.HTML != "<html><body>hi</body></html>" {
+		t.Fatalf("unexpected html: %q", envelope.HTML)
+	}
+	if len(envelope.XHR) != 3 {
+		t.Fatalf("expected 3 xhr captures, got %d", len(envelope.XHR))
+	}
+	if got := envelope.XHR[0].Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("expected capture headers to be canonicalized, got %q", got)
+	}
+
+	report := envelope.JSInstructionsReport
+	if report == nil || report.InstructionsFailed != 1 || len(report.Failed()) != 1 ||
+		report.Failed()[0].Instruction != scraperapi.JSActionWaitFor {
+		t.Fatalf("unexpected js instructions report: %+v", report)
+	}
+
+	screenshot, err := envelope.Screenshot.Bytes()
+	if err != nil || string(screenshot) != "hello" {
+		t.Fatalf("unexpected screenshot bytes: %q (%v)", screenshot, err)
+	}
+}
+
+func TestXHRCapturesFilters(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(jsonResponseBody)) })
+	envelope, err := res.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := envelope.XHR.ByMethod("post"); len(got) != 1 || got[0].URL != "https://example.com/api/track" {
+		t.Fatalf("ByMethod: unexpected captures %+v", got)
+	}
+	if got := envelope.XHR.ByURLContains("/api/"); len(got) != 2 {
+		t.Fatalf("ByURLContains: expected 2 captures, got %d", len(got))
+	}
+
+	products := envelope.XHR.ByURL(regexp.MustCompile(`/api/products\b`)).ByMethod(http.MethodGet)
+	if len(products) != 1 {
+		t.Fatalf("ByURL+ByMethod: expected 1 capture, got %d", len(products))
+	}
+	var payload struct {
+		Items []int `json:"items"`
+	}
+	if err := products[0].DecodeBody(&payload); err != nil || len(payload.Items) != 3 {
+		t.Fatalf("DecodeBody: unexpected payload %+v (%v)", payload, err)
+	}
+}
+
+func TestResponseJSONRejectsNonJSONBodies(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html></html>"))
+	})
+
+	_, err := res.JSON()
+	var unexpected scraperapi.UnexpectedContentError
+	if !errors.As(err, &unexpected) {
+		t.Fatalf("expected UnexpectedContentError, got %v", err)
+	}
+	if unexpected.ContentType != "text/html" {
+		t.Fatalf("expected the content type to be reported, got %q", unexpected.ContentType)
+	}
+}
+
+func TestResponseJSONWrapsProblemOnError(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"title":"Could not get content","status":422,"code":"RESP001"}`))
+	})
+
+	_, err := res.JSON()
+	var prob *problem.Problem
+	if !errors.As(err, &prob) || prob.Code != "RESP001" {
+		t.Fatalf("expected the error to wrap the response problem, got %v", err)
+	}
+}
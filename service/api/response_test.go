@@ -2,11 +2,15 @@ package scraperapi_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
 )
 
 func doGet(t *testing.T, handler http.HandlerFunc) *scraperapi.Response {
@@ -110,8 +114,47 @@ func TestResponseProblemIsNilWhenContentTypeIsNotProblemJSON(t *testing.T) {
 	if res.Problem() != nil {
 		t.Fatal("expected no Problem for a non-problem+json error body")
 	}
-	if res.Error() != nil {
-		t.Fatal("expected Error() to be nil when there is no parseable problem")
+	var httpErr scraperapi.HTTPError
+	if !errors.As(res.Error(), &httpErr) {
+		t.Fatalf("expected Error() to return an HTTPError, got %v", res.Error())
+	}
+	if httpErr.StatusCode != http.StatusInternalServerError || httpErr.Body != "internal error" || httpErr.Problem != nil {
+		t.Fatalf("unexpected HTTPError: %+v", httpErr)
+	}
+}
+
+func TestResponseProblemAcceptsCharsetAndKeepsExtensionMembers(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+		w.WriteHeader(http.StatusPaymentRequired)
+		_, _ = w.Write([]byte(`{"code":"AUTH010","title":"Domain not enabled","status":402,"domain":"example.com"}`))
+	})
+
+	prob := res.Problem()
+	if prob == nil || prob.Code != "AUTH010" {
+		t.Fatalf("expected the problem to be parsed despite the charset, got %+v", prob)
+	}
+	if prob.Extras["domain"] != "example.com" {
+		t.Fatalf("expected the extension members to be kept, got %+v", prob.Extras)
+	}
+	if !errors.Is(res.Error(), problem.ErrDomainNotEnabled) {
+		t.Fatalf("expected Error() to match the problem sentinel, got %v", res.Error())
+	}
+}
+
+func TestResponseErrorTruncatesLongBodies(t *testing.T) {
+	res := doGet(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(strings.Repeat("é", 1000)))
+	})
+
+	var httpErr scraperapi.HTTPError
+	if !errors.As(res.Error(), &httpErr) {
+		t.Fatalf("expected Error() to return an HTTPError, got %v", res.Error())
+	}
+	if len(httpErr.Body) > 512 || !utf8.ValidString(httpErr.Body) || httpErr.Body == "" {
+		t.Fatalf("expected a valid snippet of at most 512 bytes, got %d bytes", len(httpErr.Body))
 	}
 }
 
@@ -0,0 +1,208 @@
+package scraperapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
+)
+
+// backoffJitter is the +/- jitter applied to the backoff of DefaultRetryPolicy, like the Batch API SDK transport.
+const backoffJitter = 0.2
+
+// permanentProblemCodePrefixes are the prefixes of the problem codes, missing from the problem catalog, of failures that retrying
+// cannot fix: authentication and billing errors, and invalid requests.
+var permanentProblemCodePrefixes = []string{"AUTH", "REQS"}
+
+// RetryPolicy decides whether a failed request is retried, and how long to wait before retrying it.
+type RetryPolicy interface {
+	// Retry is called after every failed attempt: when the request could not be sent (err is set, and response is nil), or the
+	// response is an error (see Response.IsError). attempt is 1 for the first attempt, and prob is the problem of the response,
+	// if any. Successful responses are never retried.
+	Retry(attempt int, response *Response, prob *problem.Problem, err error) (retry bool, wait time.Duration)
+}
+
+// RetryPolicyFunc is an adapter to use an ordinary function as a RetryPolicy.
+type RetryPolicyFunc func(attempt int, response *Response, prob *problem.Problem, err error) (retry bool, wait time.Duration)
+
+// Retry calls f(attempt, response, prob, err).
+func (f RetryPolicyFunc) Retry(attempt int, response *Response, prob *problem.Problem, err error) (retry bool, wait time.Duration) {
+	return f(attempt, response, prob, err)
+}
+
+// DefaultRetryPolicy is the retry policy of the client, configured with WithMaxRetryCount, WithRetryWaitTime and
+// WithRetryMaxWaitTime. It retries up to MaxRetries times the requests that could not be sent, and the responses with a 422, 429
+// or 500 status code, except those whose problem code says the failure is permanent (e.g. AUTH010, or invalid parameters).
+//
+// It waits for the time set by the Retry-After header, if any, or for a jittered exponential backoff starting at WaitTime. Either
+// wait is capped at MaxWaitTime, when set.
+type DefaultRetryPolicy struct {
+	MaxRetries  int
+	WaitTime    time.Duration
+	MaxWaitTime time.Duration
+}
+
+// Retry implements RetryPolicy.
+func (p DefaultRetryPolicy) Retry(attempt int, response *Response, prob *problem.Problem, err error) (retry bool, wait time.Duration) {
+	if attempt > p.MaxRetries {
+		return false, 0
+	}
+
+	if err != nil {
+		// context cancellation/timeout is never retried — the caller set that budget
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false, 0
+		}
+		return true, p.backoff(attempt)
+	}
+
+	if !slices.Contains(retryableStatusCodes, response.StatusCode()) || isPermanentProblem(prob) {
+		return false, 0
+	}
+
+	if wait, ok := retryAfterDuration(response.Header()); ok {
+		if p.MaxWaitTime > 0 {
+			wait = min(wait, p.MaxWaitTime)
+		}
+		return true, wait
+	}
+	return true, p.backoff(attempt)
+}
+
+// backoff returns the jittered exponential backoff before retrying the given attempt.
+func (p DefaultRetryPolicy) backoff(attempt int) time.Duration {
+	base := p.WaitTime << min(attempt-1, 16)
+	if p.MaxWaitTime > 0 && (base > p.MaxWaitTime || base <= 0) {
+		base = p.MaxWaitTime
+	}
+	jittered := float64(base) * (1 + (rand.Float64()*2-1)*backoffJitter) //nolint:gosec // timing jitter, not security-sensitive
+	return time.Duration(jittered)
+}
+
+// isPermanentProblem reports whether a problem describes a failure that retrying cannot fix, as told by the problem catalog (e.g.
+// AUTH010, a domain not enabled for the Extract beta), or by the prefix of unknown codes. Rate limits (429 Too Many Requests) are
+// never permanent.
+func isPermanentProblem(prob *problem.Problem) bool {
+	if prob == nil || prob.Status == http.StatusTooManyRequests {
+		return false
+	}
+	if prob.ProblemCode().Known() {
+		return !prob.Retryable()
+	}
+
+	code := strings.ToUpper(prob.Code)
+	return slices.ContainsFunc(permanentProblemCodePrefixes, func(prefix string) bool { return strings.HasPrefix(code, prefix) })
+}
+
+// WithRetryPolicy returns an Option which configures the policy that decides whether failed requests are retried. It replaces the
+// DefaultRetryPolicy configured with WithMaxRetryCount, WithRetryWaitTime and WithRetryMaxWaitTime.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return newFuncDialOption(func(o *options) {
+		o.retryPolicy = policy
+	})
+}
+
+// executeWithRetry sends the request through the attempt middleware of the client, retrying it as long as the retry policy of the
+// client says so. settle settles the budget reservation of the first attempt; every attempt is charged to the budgets and recorded
+// in the spend meter, and the worst-case cost of every retry is reserved before it is sent, so retries stop with a
+// BudgetExceededError once the budgets are exhausted.
+func (c *Client) executeWithRetry(ctx context.Context, req *Request, settle func(*Response)) (*Response, error) {
+	for attempt := 1; ; attempt++ {
+		attemptReq := *req
+		attemptReq.Attempt = attempt
+		response, err := c.attemptHandler(ctx, &attemptReq)
+		if err != nil {
+			response = nil
+		} else {
+			c.afterResponse(ctx, response)
+		}
+		settle(response)
+
+		var prob *problem.Problem
+		if response != nil {
+			if !response.IsError() {
+				return response, nil
+			}
+			prob = response.Problem()
+		}
+
+		retry, wait := c.retryPolicy().Retry(attempt, response, prob, err)
+		if !retry || ctx.Err() != nil {
+			return response, err
+		}
+
+		attrs := []any{
+			slog.String("method", req.Method), slog.String("target_url", req.TargetURL), slog.Int("attempt", attempt),
+			slog.Duration("wait", wait),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
+		} else {
+			attrs = append(attrs, slog.Int("status", response.StatusCode()))
+			if prob != nil {
+				attrs = append(attrs, slog.String("problem_code", prob.Code))
+			}
+		}
+		c.log.InfoContext(ctx, "scraperapi: retrying request", attrs...)
+		c.metrics.AddCounter(MetricRetries, map[string]string{"method": req.Method}, 1)
+		if !sleepCtx(ctx, wait) {
+			return response, ctx.Err()
+		}
+
+		if settle, err = c.reserveBudget(ctx, req.Params); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryPolicy returns the retry policy of the client.
+func (c *Client) retryPolicy() RetryPolicy {
+	if c.cfg.retryPolicy != nil {
+		return c.cfg.retryPolicy
+	}
+
+	return DefaultRetryPolicy{
+		MaxRetries:  c.cfg.retryOptions.maxRetryCount,
+		WaitTime:    c.cfg.retryOptions.retryWaitTime,
+		MaxWaitTime: c.cfg.retryOptions.retryMaxWaitTime,
+	}
+}
+
+// bufferErrorBody reads the body of a streamed error response, up to maxStreamErrorBodySize, and closes it.
+func bufferErrorBody(res *resty.Response) error {
+	rawBody := res.RawBody()
+	if rawBody == nil {
+		return nil
+	}
+	defer rawBody.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rawBody, maxStreamErrorBodySize))
+	if err != nil {
+		return err
+	}
+	res.SetBody(data)
+	return nil
+}
+
+// sleepCtx sleeps for d or returns false early if ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
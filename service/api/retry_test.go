@@ -0,0 +1,152 @@
+package scraperapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
+)
+
+func TestDefaultRetryPolicyDoesNotRetryPermanentProblems(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", problem.ContentTypeJSON)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"code":"REQS001","title":"Invalid parameters","status":422}`))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithMaxRetryCount(3),
+		scraperapi.WithRetryWaitTime(time.Millisecond),
+	)
+
+	res, err := client.Get(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Problem() == nil || res.Problem().Code != "REQS001" {
+		t.Fatalf("expected the REQS001 problem to be returned, got %v", res.Problem())
+	}
+	if hits.Load() != 1 {
+		t.Fatalf("expected a permanent problem not to be retried, got %d attempts", hits.Load())
+	}
+}
+
+func TestDefaultRetryPolicyHonorsRetryAfter(t *testing.T) {
+	policy := scraperapi.DefaultRetryPolicy{MaxRetries: 2, WaitTime: time.Millisecond, MaxWaitTime: time.Second}
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0.2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithRetryPolicy(policy),
+	)
+
+	start := time.Now()
+	res, err := client.Get(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.IsSuccess() || hits.Load() != 2 {
+		t.Fatalf("expected the 429 to be retried once, got status %d after %d attempts", res.StatusCode(), hits.Load())
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("expected the retry to wait for the Retry-After time, waited %s", elapsed)
+	}
+}
+
+func TestDefaultRetryPolicyCapsRetryAfterAtMaxWaitTime(t *testing.T) {
+	response := scraperapi.NewResponse(http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}}, nil)
+
+	capped := scraperapi.DefaultRetryPolicy{MaxRetries: 1, WaitTime: time.Millisecond, MaxWaitTime: 2 * time.Second}
+	if retry, wait := capped.Retry(1, response, nil, nil); !retry || wait != 2*time.Second {
+		t.Fatalf("expected the Retry-After time to be capped at 2s, got retry=%v wait=%s", retry, wait)
+	}
+
+	uncapped := scraperapi.DefaultRetryPolicy{MaxRetries: 1, WaitTime: time.Millisecond}
+	if retry, wait := uncapped.Retry(1, response, nil, nil); !retry || wait != time.Hour {
+		t.Fatalf("expected the Retry-After time to be honored without MaxWaitTime, got retry=%v wait=%s", retry, wait)
+	}
+}
+
+func TestWithRetryPolicySeesProblemAndAttempt(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", problem.ContentTypeJSON)
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"code":"CTX0002","title":"Timeout","status":502}`))
+	}))
+	defer server.Close()
+
+	var attempts []int
+	policy := scraperapi.RetryPolicyFunc(func(attempt int, _ *scraperapi.Response, prob *problem.Problem, _ error) (bool, time.Duration) {
+		attempts = append(attempts, attempt)
+		return prob != nil && prob.Code == "CTX0002" && attempt < 3, 0
+	})
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithRetryPolicy(policy))
+
+	res, err := client.Get(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.StatusCode() != http.StatusBadGateway || hits.Load() != 3 {
+		t.Fatalf("expected 3 attempts ending in a 502, got status %d after %d attempts", res.StatusCode(), hits.Load())
+	}
+	if len(attempts) != 3 || attempts[0] != 1 || attempts[2] != 3 {
+		t.Fatalf("expected the policy to see attempts 1 to 3, got %v", attempts)
+	}
+}
+
+func TestRetriedStreamReturnsBufferedProblem(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", problem.ContentTypeJSON)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"code":"RESP001","title":"Could not get content","status":500}`))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(
+		scraperapi.WithBaseURL(server.URL),
+		scraperapi.WithAPIKey("k"),
+		scraperapi.WithMaxRetryCount(2),
+		scraperapi.WithRetryWaitTime(time.Millisecond),
+		scraperapi.WithMaxConcurrentRequests(1),
+	)
+
+	res, err := client.GetStream(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Close()
+	if hits.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", hits.Load())
+	}
+	if res.Problem() == nil || res.Problem().Code != "RESP001" {
+		t.Fatalf("expected the problem of the last attempt, got %v", res.Problem())
+	}
+	if stats := client.Concurrency(); stats.InFlight != 0 {
+		t.Fatalf("expected the concurrency slot to be released, got %+v", stats)
+	}
+}
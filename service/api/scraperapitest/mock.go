@@ -0,0 +1,163 @@
+package scraperapitest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+// ErrNotMocked is returned by the methods of a MockClient without a function to answer them.
+var ErrNotMocked = errors.New("scraperapitest: method not mocked")
+
+// ScrapeFunc answers a request sent to a MockClient.
+type ScrapeFunc func(ctx context.Context, method, targetURL string, params *scraperapi.RequestParameters, body any) (*scraperapi.Response, error)
+
+// Call is a call to a method of a MockClient.
+type Call struct {
+	// Name is the name of the method called, e.g. "Get".
+	Name      string
+	Method    string
+	TargetURL string
+	Params    *scraperapi.RequestParameters
+	Body      any
+}
+
+// EscalateFunc answers a call to MockClient.Escalate.
+type EscalateFunc func(ctx context.Context, targetURL string) (*scraperapi.EscalationResult, error)
+
+// MockClient is a fake scraperapi.IClient, to inject into code under test instead of a scraperapi.Client. Every method answers
+// with ScrapeFunc, called with the HTTP method of the request, and records the call. Build responses with scraperapi.NewResponse.
+// It is safe for concurrent use.
+type MockClient struct {
+	// ScrapeFunc answers every request, whatever the method used to send it. If nil, the methods return ErrNotMocked.
+	ScrapeFunc ScrapeFunc
+	// EscalateFunc answers Escalate. If nil, Escalate sends a single GET request with ScrapeFunc, and returns its response as the
+	// first step of the escalation ladder.
+	EscalateFunc EscalateFunc
+	// ConcurrencyStats is what Concurrency returns. Its zero value tells the client is not limited.
+	ConcurrencyStats scraperapi.ConcurrencyStats
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// MockClient must implement scraperapi.IClient.
+var _ scraperapi.IClient = (*MockClient)(nil)
+
+// Calls returns the calls made so far, in order.
+func (m *MockClient) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.calls)
+}
+
+// record records a call.
+func (m *MockClient) record(call Call) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, call)
+}
+
+// call records a call, and answers it with ScrapeFunc.
+func (m *MockClient) call(
+	ctx context.Context, name, method, targetURL string, params *scraperapi.RequestParameters, body any,
+) (*scraperapi.Response, error) {
+	m.record(Call{Name: name, Method: method, TargetURL: targetURL, Params: params, Body: body})
+
+	m.mu.Lock()
+	scrape := m.ScrapeFunc
+	m.mu.Unlock()
+
+	if scrape == nil {
+		return nil, ErrNotMocked
+	}
+	return scrape(ctx, method, targetURL, params, body)
+}
+
+// Scrape implements scraperapi.IClient.
+func (m *MockClient) Scrape(
+	ctx context.Context, method, targetURL string, params *scraperapi.RequestParameters, body any,
+) (*scraperapi.Response, error) {
+	return m.call(ctx, "Scrape", method, targetURL, params, body)
+}
+
+// ScrapeStream implements scraperapi.IClient.
+func (m *MockClient) ScrapeStream(
+	ctx context.Context, method, targetURL string, params *scraperapi.RequestParameters, body any,
+) (*scraperapi.Response, error) {
+	return m.call(ctx, "ScrapeStream", method, targetURL, params, body)
+}
+
+// Get implements scraperapi.IClient.
+func (m *MockClient) Get(ctx context.Context, targetURL string, params *scraperapi.RequestParameters) (*scraperapi.Response, error) {
+	return m.call(ctx, "Get", http.MethodGet, targetURL, params, nil)
+}
+
+// GetStream implements scraperapi.IClient.
+func (m *MockClient) GetStream(ctx context.Context, targetURL string, params *scraperapi.RequestParameters) (*scraperapi.Response, error) {
+	return m.call(ctx, "GetStream", http.MethodGet, targetURL, params, nil)
+}
+
+// Fetch implements scraperapi.IClient.
+func (m *MockClient) Fetch(ctx context.Context, targetURL string, params *scraperapi.RequestParameters) (*scraperapi.Response, error) {
+	return m.call(ctx, "Fetch", http.MethodGet, targetURL, params, nil)
+}
+
+// Extract implements scraperapi.IClient.
+func (m *MockClient) Extract(ctx context.Context, targetURL string, params *scraperapi.RequestParameters) (*scraperapi.Response, error) {
+	return m.call(ctx, "Extract", http.MethodGet, targetURL, params, nil)
+}
+
+// Post implements scraperapi.IClient.
+func (m *MockClient) Post(
+	ctx context.Context, targetURL string, params *scraperapi.RequestParameters, body any,
+) (*scraperapi.Response, error) {
+	return m.call(ctx, "Post", http.MethodPost, targetURL, params, body)
+}
+
+// Put implements scraperapi.IClient.
+func (m *MockClient) Put(
+	ctx context.Context, targetURL string, params *scraperapi.RequestParameters, body any,
+) (*scraperapi.Response, error) {
+	return m.call(ctx, "Put", http.MethodPut, targetURL, params, body)
+}
+
+// Escalate implements scraperapi.IClient. The call is recorded with the parameters of the first step of the default escalation
+// ladder.
+func (m *MockClient) Escalate(ctx context.Context, targetURL string) (*scraperapi.EscalationResult, error) {
+	params := scraperapi.DefaultEscalationLadder(scraperapi.RequestParameters{}, "")[0]
+
+	m.mu.Lock()
+	escalate := m.EscalateFunc
+	m.mu.Unlock()
+	if escalate != nil {
+		m.record(Call{Name: "Escalate", Method: http.MethodGet, TargetURL: targetURL, Params: &params})
+		return escalate(ctx, targetURL)
+	}
+
+	response, err := m.call(ctx, "Escalate", http.MethodGet, targetURL, &params, nil)
+	if err != nil {
+		return &scraperapi.EscalationResult{}, err
+	}
+	result := &scraperapi.EscalationResult{
+		Response: response,
+		Attempts: []scraperapi.EscalationAttempt{
+			{Params: params, StatusCode: response.StatusCode(), Cost: response.Cost()},
+		},
+	}
+	return result, response.Error()
+}
+
+// Concurrency returns ConcurrencyStats, like scraperapi.Client.Concurrency, e.g. to use the MockClient as a crawl.Client.
+func (m *MockClient) Concurrency() scraperapi.ConcurrencyStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.ConcurrencyStats
+}
@@ -0,0 +1,90 @@
+package scraperapitest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/scraperapitest"
+)
+
+// title fetches a page through any scraperapi.IClient, as code under test would.
+func title(ctx context.Context, client scraperapi.IClient, targetURL string) (string, error) {
+	res, err := client.Get(ctx, targetURL, &scraperapi.RequestParameters{CSSExtractor: `{"title":"title"}`})
+	if err != nil {
+		return "", err
+	}
+	if err = res.Error(); err != nil {
+		return "", err
+	}
+	return res.String(), nil
+}
+
+func TestMockClientAnswersWithScrapeFunc(t *testing.T) {
+	mock := &scraperapitest.MockClient{
+		ScrapeFunc: func(_ context.Context, method, targetURL string, _ *scraperapi.RequestParameters, _ any) (*scraperapi.Response, error) {
+			if targetURL == "https://example.com/missing" {
+				return scraperapi.NewResponse(http.StatusNotFound, nil, nil), nil
+			}
+			return scraperapi.NewResponse(http.StatusOK, http.Header{"Content-Type": {"application/json"}}, []byte(`{"title":"Example"}`)), nil
+		},
+	}
+
+	got, err := title(context.Background(), mock, "https://example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != `{"title":"Example"}` {
+		t.Fatalf("unexpected title %q", got)
+	}
+
+	var httpErr scraperapi.HTTPError
+	if _, err = title(context.Background(), mock, "https://example.com/missing"); !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a 404 HTTPError, got %v", err)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 2 || calls[0].Name != "Get" || calls[0].Method != http.MethodGet || calls[0].Params.CSSExtractor == "" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+}
+
+func TestMockClientWithoutScrapeFuncReturnsErrNotMocked(t *testing.T) {
+	mock := &scraperapitest.MockClient{}
+	if _, err := mock.Post(context.Background(), "https://example.com", nil, "body"); !errors.Is(err, scraperapitest.ErrNotMocked) {
+		t.Fatalf("expected ErrNotMocked, got %v", err)
+	}
+	if calls := mock.Calls(); len(calls) != 1 || calls[0].Body != "body" {
+		t.Fatalf("expected the call to be recorded, got %+v", calls)
+	}
+}
+
+func TestMockClientEscalate(t *testing.T) {
+	mock := &scraperapitest.MockClient{
+		ScrapeFunc: func(_ context.Context, _, _ string, _ *scraperapi.RequestParameters, _ any) (*scraperapi.Response, error) {
+			return scraperapi.NewResponse(http.StatusOK, nil, []byte("<html></html>")), nil
+		},
+	}
+
+	result, err := mock.Escalate(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Step != 0 || result.Response.String() != "<html></html>" || len(result.Attempts) != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	mock.EscalateFunc = func(_ context.Context, _ string) (*scraperapi.EscalationResult, error) {
+		return &scraperapi.EscalationResult{Step: 2}, nil
+	}
+	if result, err = mock.Escalate(context.Background(), "https://example.com"); err != nil || result.Step != 2 {
+		t.Fatalf("expected EscalateFunc to answer, got %+v (%v)", result, err)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 2 || calls[0].Name != "Escalate" || calls[1].Name != "Escalate" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+}
@@ -0,0 +1,72 @@
+package scraperapitest
+
+import "strings"
+
+// DefaultAPIKey is the API key the Server accepts unless set with WithAPIKey.
+const DefaultAPIKey = "test-api-key" //nolint:gosec // fake API key of the fake server
+
+// Option configures the Server.
+type Option interface {
+	apply(*options)
+}
+
+// options holds the configuration for the Server
+type options struct {
+	// apiKey is the API key the server accepts. Defaults to DefaultAPIKey.
+	apiKey string
+	// concurrencyLimit is the number of requests the server handles at a time; the others get a 429 Too Many Requests. Defaults to
+	// 0 (no limit).
+	concurrencyLimit int
+	// extractDomains are the domains enabled for ExtractModeAuto; the others get a 402 with problem code AUTH010. Defaults to none.
+	extractDomains map[string]bool
+}
+
+// defaultOptions returns the default options for the Server.
+func defaultOptions() options {
+	return options{
+		apiKey:         DefaultAPIKey,
+		extractDomains: make(map[string]bool),
+	}
+}
+
+// funcOption wraps a function that modifies options into an implementation of the Option interface.
+type funcOption struct {
+	f func(*options)
+}
+
+func (fo *funcOption) apply(o *options) {
+	fo.f(o)
+}
+
+func newFuncOption(f func(*options)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+// WithAPIKey returns an Option which configures the API key the server accepts. Requests without it get a 401 with problem code
+// AUTH001, and requests with another one a 401 with problem code AUTH002. Defaults to DefaultAPIKey.
+func WithAPIKey(apiKey string) Option {
+	return newFuncOption(func(o *options) {
+		o.apiKey = apiKey
+	})
+}
+
+// WithConcurrencyLimit returns an Option which configures the number of requests the server handles at a time, as the concurrency
+// limit of a plan. Requests over the limit get a 429 Too Many Requests with problem code AUTH006, and every response reports the
+// limit and the slots left in the Concurrency-Limit and Concurrency-Remaining headers. Defaults to 0 (no limit).
+func WithConcurrencyLimit(limit int) Option {
+	return newFuncOption(func(o *options) {
+		o.concurrencyLimit = limit
+	})
+}
+
+// WithExtractDomains returns an Option which enables the given domains for ExtractModeAuto. Requests to other domains with
+// ExtractModeAuto get a 402 with problem code AUTH010, as during the Extract beta. Defaults to none.
+func WithExtractDomains(domains ...string) Option {
+	return newFuncOption(func(o *options) {
+		for _, domain := range domains {
+			o.extractDomains[strings.TrimPrefix(strings.ToLower(domain), "www.")] = true
+		}
+	})
+}
@@ -0,0 +1,351 @@
+// Package scraperapitest provides a fake ZenRows Fetch API, served in process, to test code built on scraperapi.Client offline.
+//
+// The Server authenticates requests, validates their parameters with the same rules as the client, answers with problem details
+// like the real API, and enforces a concurrency limit. Script the responses of target URLs to test retries, the Extract fallback
+// and escalation end to end:
+//
+//	server := scraperapitest.NewServer(scraperapitest.WithConcurrencyLimit(5))
+//	defer server.Close()
+//
+//	server.Script("https://example.com",
+//		scraperapitest.Response{Code: problem.CodeUnknownError},
+//		scraperapitest.Response{Body: "<html>...</html>"},
+//	)
+//
+//	client := server.NewClient(scraperapi.WithMaxRetryCount(1))
+package scraperapitest
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
+)
+
+// Request is a request received by the Server.
+type Request struct {
+	Method    string
+	TargetURL string
+	// Params are the request parameters, as parsed from the query parameters.
+	Params *scraperapi.RequestParameters
+	// Header holds the headers of the request, custom headers included.
+	Header http.Header
+	Body   []byte
+	// N is the number of the request to the target URL, starting at 1. Only the requests that reach the handler of the target URL
+	// are counted; N is 0 for the rejected ones, e.g. over the concurrency limit.
+	N int
+}
+
+// Response is a response of the Server to a request for a target URL.
+type Response struct {
+	// Status is the status code of the response. Defaults to 200 OK, or to the status of Code if set.
+	Status int
+	// Body is the body of the target page. It is ignored if Code is set.
+	Body string
+	// Header holds the headers of the target page. They are sent with the "Z-" prefix, as the ZenRows Fetch API does.
+	Header http.Header
+	// Cookies are the cookies set by the target page, sent in Z-Set-Cookie headers.
+	Cookies []*http.Cookie
+	// Code, if set, makes the response a problem of the ZenRows Fetch API with the code, e.g. problem.CodeCouldNotGetContent for a
+	// blocked target.
+	Code problem.Code
+	// Credits is what the request is charged, reported in the X-Request-Cost header of the responses that are charged: successful
+	// ones, and those whose status is allowed by the AllowedStatusCodes of the request. Defaults to the credits of the
+	// configuration the request was sent with (see scraperapi.Tier.Credits), or of AutoTier for ModeAuto requests.
+	Credits float64
+	// AutoTier is the configuration Adaptive Stealth Mode ends up using for a ModeAuto request, which tells what it is charged.
+	// Defaults to scraperapi.TierBase. It is ignored for the other requests.
+	AutoTier scraperapi.Tier
+	// Delay is how long the response takes, e.g. to hold a concurrency slot.
+	Delay time.Duration
+}
+
+// HandlerFunc answers the requests for a target URL.
+type HandlerFunc func(req *Request) Response
+
+// Server is a fake ZenRows Fetch API. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	cfg options
+
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+	counts   map[string]int
+	requests []Request
+	inFlight int
+	nextID   int
+}
+
+// NewServer starts and returns a new Server. Close it when done.
+func NewServer(opts ...Option) *Server {
+	s := &Server{cfg: defaultOptions(), handlers: make(map[string]HandlerFunc), counts: make(map[string]int)}
+	for _, opt := range opts {
+		opt.apply(&s.cfg)
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewClient returns a scraperapi.Client sending its requests to the server with its API key. The options are applied after those.
+func (s *Server) NewClient(opts ...scraperapi.Option) *scraperapi.Client {
+	return scraperapi.NewClient(append([]scraperapi.Option{scraperapi.WithBaseURL(s.URL), scraperapi.WithAPIKey(s.cfg.apiKey)}, opts...)...)
+}
+
+// Script sets the responses to the requests for the target URL, in order. Once they are all sent, the last one is sent again.
+func (s *Server) Script(targetURL string, responses ...Response) {
+	s.Handle(targetURL, func(req *Request) Response {
+		if len(responses) == 0 {
+			return Response{}
+		}
+		return responses[min(req.N, len(responses))-1]
+	})
+}
+
+// Handle sets the handler of the requests for the target URL, e.g. to answer depending on the request parameters. Target URLs
+// without a handler get a page echoing their URL.
+func (s *Server) Handle(targetURL string, handler HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[targetURL] = handler
+}
+
+// Requests returns the requests received so far, in order, including the rejected ones.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.requests)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	body, _ := io.ReadAll(r.Body)
+	req := Request{Method: r.Method, TargetURL: query.Get("url"), Header: r.Header.Clone(), Body: body}
+
+	s.mu.Lock()
+	s.nextID++
+	w.Header().Set("X-Request-Id", "req_"+strconv.Itoa(s.nextID))
+	s.mu.Unlock()
+
+	params, code, detail := s.validate(r.Method, query)
+	req.Params = params
+
+	s.mu.Lock()
+	index := len(s.requests)
+	s.requests = append(s.requests, req)
+	handler := s.handlers[req.TargetURL]
+	s.mu.Unlock()
+
+	if code != "" {
+		writeProblem(w, code, 0, detail)
+		return
+	}
+
+	release, ok := s.acquire(w)
+	if !ok {
+		writeProblem(w, problem.CodeConcurrencyExceeded, 0, "the concurrency limit of the plan is exceeded")
+		return
+	}
+	defer release()
+
+	if params.Extract == scraperapi.ExtractModeAuto && !s.cfg.extractDomains[domain(req.TargetURL)] {
+		writeProblem(w, problem.CodeDomainNotEnabled, 0, "the domain is not enabled for Extract yet")
+		return
+	}
+
+	// only the requests reaching the handler use up a scripted response
+	s.mu.Lock()
+	s.counts[req.TargetURL]++
+	req.N = s.counts[req.TargetURL]
+	s.requests[index].N = req.N
+	s.mu.Unlock()
+
+	res := Response{Body: "<html><body>" + req.TargetURL + "</body></html>", Header: http.Header{"Content-Type": {"text/html"}}}
+	if handler != nil {
+		res = handler(&req)
+	}
+	s.respond(w, r, &req, res)
+}
+
+// validate authenticates a request, and parses and validates its parameters. It returns the problem code and detail of invalid
+// requests.
+func (s *Server) validate(method string, query url.Values) (*scraperapi.RequestParameters, problem.Code, string) {
+	switch apiKey := query.Get("apikey"); {
+	case apiKey == "":
+		return nil, problem.CodeAPIKeyMissing, "the apikey parameter is missing"
+	case apiKey != s.cfg.apiKey:
+		return nil, problem.CodeInvalidAPIKey, "the API key is not valid"
+	}
+
+	if method != http.MethodGet && method != http.MethodPost && method != http.MethodPut {
+		return nil, problem.CodeInvalidParameters, "the method " + method + " is not allowed"
+	}
+
+	targetURL := query.Get("url")
+	if targetURL == "" {
+		return nil, problem.CodeInvalidParameters, "the url parameter is missing"
+	}
+	if u, err := url.Parse(targetURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, problem.CodeURLNotAllowed, "the url parameter is not a valid http or https URL"
+	}
+
+	values := make(url.Values, len(query))
+	for k, v := range query {
+		if k != "apikey" && k != "url" && k != "custom_headers" {
+			values[k] = v
+		}
+	}
+	params, err := scraperapi.ParseQueryRequestParameters(values)
+	if err != nil {
+		return nil, problem.CodeInvalidParameters, err.Error()
+	}
+	if err = params.Validate(); err != nil {
+		return nil, problem.CodeInvalidParameters, err.Error()
+	}
+	return params, "", ""
+}
+
+// acquire takes a concurrency slot, if one is left, and reports the concurrency of the plan in the response headers.
+func (s *Server) acquire(w http.ResponseWriter) (release func(), ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := s.cfg.concurrencyLimit
+	if limit <= 0 {
+		return func() {}, true
+	}
+
+	w.Header().Set("Concurrency-Limit", strconv.Itoa(limit))
+	if s.inFlight >= limit {
+		w.Header().Set("Concurrency-Remaining", "0")
+		return nil, false
+	}
+	s.inFlight++
+	w.Header().Set("Concurrency-Remaining", strconv.Itoa(limit-s.inFlight))
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.inFlight--
+	}, true
+}
+
+// respond writes a scripted response.
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, req *Request, res Response) {
+	if res.Delay > 0 {
+		select {
+		case <-time.After(res.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if res.Code != "" {
+		writeProblem(w, res.Code, res.Status, "")
+		return
+	}
+
+	for key, values := range res.Header {
+		for _, v := range values {
+			w.Header().Add("Z-"+key, v)
+		}
+		if strings.EqualFold(key, "Content-Type") {
+			w.Header().Set("Content-Type", values[0])
+		}
+	}
+	for _, cookie := range res.Cookies {
+		w.Header().Add("Z-Set-Cookie", cookie.String())
+	}
+
+	status := res.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	estimate := scraperapi.EstimateCost(req.Params, 1)
+	if status < http.StatusBadRequest || slices.Contains(estimate.ChargedStatusCodes, status) {
+		credits := res.Credits
+		if credits == 0 {
+			credits = requestCredits(estimate, res.AutoTier)
+		}
+		w.Header().Set("X-Request-Cost", strconv.FormatFloat(credits, 'f', -1, 64))
+	}
+
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, res.Body)
+}
+
+// requestCredits returns the credits charged for a request, from the same cost table as the client: the credits of the tier the
+// client estimates it for, or of autoTier, TierBase if empty, for ModeAuto requests.
+func requestCredits(estimate scraperapi.CostEstimate, autoTier scraperapi.Tier) float64 {
+	tier := scraperapi.TierBase
+	if len(estimate.Breakdown) > 0 {
+		tier = estimate.Breakdown[0].Tier
+	}
+	if tier == scraperapi.TierAuto {
+		tier = cmp.Or(autoTier, scraperapi.TierBase)
+	}
+
+	credits, _ := tier.Credits()
+	return float64(credits)
+}
+
+// problemStatuses are the status codes the ZenRows Fetch API answers the problem codes with.
+var problemStatuses = map[problem.Code]int{
+	problem.CodeAPIKeyMissing:       http.StatusUnauthorized,
+	problem.CodeInvalidAPIKey:       http.StatusUnauthorized,
+	problem.CodeAPIKeyRevoked:       http.StatusUnauthorized,
+	problem.CodeUsageExceeded:       http.StatusPaymentRequired,
+	problem.CodeNoCreditAvailable:   http.StatusPaymentRequired,
+	problem.CodeDomainNotEnabled:    http.StatusPaymentRequired,
+	problem.CodeURLNotAllowed:       http.StatusBadRequest,
+	problem.CodeEscalationRequired:  http.StatusBadRequest,
+	problem.CodeInvalidParameters:   http.StatusBadRequest,
+	problem.CodeCouldNotGetContent:  http.StatusUnprocessableEntity,
+	problem.CodeIPBlocked:           http.StatusForbidden,
+	problem.CodeConcurrencyExceeded: http.StatusTooManyRequests,
+	problem.CodeContextCanceled:     http.StatusInternalServerError,
+	problem.CodeOperationTimeout:    http.StatusGatewayTimeout,
+	problem.CodeUnknownError:        http.StatusInternalServerError,
+}
+
+// writeProblem writes a problem with the code. The status defaults to the one of the code, or 500 for unknown codes.
+func writeProblem(w http.ResponseWriter, code problem.Code, status int, detail string) {
+	if status == 0 {
+		status = problemStatuses[code.Normalize()]
+	}
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+
+	w.Header().Set("Content-Type", problem.ContentTypeJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(problem.Problem{
+		Code:   string(code),
+		Detail: detail,
+		Status: status,
+		Title:  http.StatusText(status),
+		Type:   fmt.Sprintf("https://docs.zenrows.com/api-error-codes#%s", code),
+	})
+}
+
+// domain returns the host of a URL, in lower case and without the www. prefix.
+func domain(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
@@ -0,0 +1,294 @@
+package scraperapitest_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/pkg/problem"
+	"github.com/zenrows/zenrows-go-sdk/service/api/scraperapitest"
+)
+
+func TestServerAnswersWithTargetHeadersAndCookies(t *testing.T) {
+	server := scraperapitest.NewServer()
+	defer server.Close()
+
+	server.Script("https://example.com", scraperapitest.Response{
+		Body:    "<html>hello</html>",
+		Header:  http.Header{"Content-Type": {"text/html"}, "X-Target": {"1"}},
+		Cookies: []*http.Cookie{{Name: "session", Value: "abc"}},
+	})
+
+	res, err := server.NewClient().Get(context.Background(), "https://example.com", &scraperapi.RequestParameters{JSRender: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.String() != "<html>hello</html>" || res.TargetHeaders().Get("Z-X-Target") != "1" {
+		t.Fatalf("unexpected response %q, target headers %v", res.String(), res.TargetHeaders())
+	}
+	if cookies := res.TargetCookies(); len(cookies) != 1 || cookies[0].Name != "session" || cookies[0].Value != "abc" {
+		t.Fatalf("unexpected cookies %v", cookies)
+	}
+	if cost := res.Cost(); cost.Credits != scraperapi.JSCredits || cost.RequestID == "" {
+		t.Fatalf("expected the credits of a JS rendering request and a request ID, got %+v", cost)
+	}
+}
+
+func TestServerValidatesRequests(t *testing.T) {
+	server := scraperapitest.NewServer()
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		query url.Values
+		code  problem.Code
+	}{
+		{"missing API key", url.Values{"url": {"https://example.com"}}, problem.CodeAPIKeyMissing},
+		{"invalid API key", url.Values{"apikey": {"nope"}, "url": {"https://example.com"}}, problem.CodeInvalidAPIKey},
+		{"missing url", url.Values{"apikey": {scraperapitest.DefaultAPIKey}}, problem.CodeInvalidParameters},
+		{
+			"invalid parameters",
+			url.Values{"apikey": {scraperapitest.DefaultAPIKey}, "url": {"https://example.com"}, "wait": {"1000"}},
+			problem.CodeInvalidParameters,
+		},
+		{
+			"unknown parameters",
+			url.Values{"apikey": {scraperapitest.DefaultAPIKey}, "url": {"https://example.com"}, "nope": {"1"}},
+			problem.CodeInvalidParameters,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := http.Get(server.URL + "?" + tt.query.Encode())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer res.Body.Close()
+
+			if res.Header.Get("Content-Type") != problem.ContentTypeJSON {
+				t.Fatalf("expected a problem, got content type %q", res.Header.Get("Content-Type"))
+			}
+			body, _ := io.ReadAll(res.Body)
+			prob, err := problem.Parse(body)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if prob.ProblemCode() != tt.code || prob.Status != res.StatusCode || res.StatusCode < http.StatusBadRequest {
+				t.Fatalf("expected %s, got %s with status %d", tt.code, prob.Code, res.StatusCode)
+			}
+		})
+	}
+
+	requests := server.Requests()
+	if len(requests) != len(tests) {
+		t.Fatalf("expected %d requests, got %d", len(tests), len(requests))
+	}
+}
+
+func TestServerScriptsRetries(t *testing.T) {
+	server := scraperapitest.NewServer()
+	defer server.Close()
+
+	server.Script("https://example.com",
+		scraperapitest.Response{Code: problem.CodeUnknownError},
+		scraperapitest.Response{Body: "ok"},
+	)
+
+	client := server.NewClient(scraperapi.WithMaxRetryCount(1), scraperapi.WithRetryWaitTime(time.Millisecond))
+	res, err := client.Get(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.String() != "ok" {
+		t.Fatalf("expected the retried request to succeed, got %d %q", res.StatusCode(), res.String())
+	}
+	if requests := server.Requests(); len(requests) != 2 || requests[1].N != 2 {
+		t.Fatalf("expected 2 requests, got %+v", requests)
+	}
+}
+
+func TestServerRejectsExtractOnDomainsNotEnabled(t *testing.T) {
+	server := scraperapitest.NewServer(scraperapitest.WithExtractDomains("enabled.com"))
+	defer server.Close()
+	client := server.NewClient()
+
+	res, err := client.Extract(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.ExtractSource() != scraperapi.ExtractSourceAutoParse {
+		t.Fatalf("expected the AutoParse fallback, got %q", res.ExtractSource())
+	}
+
+	res, err = client.Extract(context.Background(), "https://example.com", &scraperapi.RequestParameters{DisableAutoparseFallback: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !errors.Is(res.Error(), problem.ErrDomainNotEnabled) {
+		t.Fatalf("expected AUTH010, got %v", res.Error())
+	}
+
+	res, err = client.Extract(context.Background(), "https://www.enabled.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.ExtractSource() != scraperapi.ExtractSourceExtract || !res.IsSuccess() {
+		t.Fatalf("expected Extract to succeed on an enabled domain, got %d from %q", res.StatusCode(), res.ExtractSource())
+	}
+}
+
+func TestServerHandlesEscalation(t *testing.T) {
+	server := scraperapitest.NewServer()
+	defer server.Close()
+
+	// the target is blocked unless premium proxies are used
+	server.Handle("https://example.com", func(req *scraperapitest.Request) scraperapitest.Response {
+		if !req.Params.UsePremiumProxies {
+			return scraperapitest.Response{Code: problem.CodeIPBlocked}
+		}
+		return scraperapitest.Response{Body: "ok"}
+	})
+
+	result, err := server.NewClient().Escalate(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Step != 2 || result.Response.String() != "ok" {
+		t.Fatalf("expected the premium proxy step to succeed, got step %d with %q", result.Step, result.Response.String())
+	}
+	if result.Credits() != scraperapi.PremiumProxyCredits {
+		t.Fatalf("expected only the successful attempt to be charged, got %g credits", result.Credits())
+	}
+}
+
+func TestServerEnforcesTheConcurrencyLimit(t *testing.T) {
+	server := scraperapitest.NewServer(scraperapitest.WithConcurrencyLimit(1))
+	defer server.Close()
+
+	server.Script("https://example.com/slow", scraperapitest.Response{Body: "slow", Delay: 200 * time.Millisecond})
+	client := server.NewClient()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = client.Get(context.Background(), "https://example.com/slow", nil)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	res, err := client.Get(context.Background(), "https://example.com/fast", nil)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.StatusCode() != http.StatusTooManyRequests || !errors.Is(res.Error(), problem.ErrConcurrencyExceeded) {
+		t.Fatalf("expected a 429 with AUTH006, got %d", res.StatusCode())
+	}
+	if res.Header().Get("Concurrency-Limit") != "1" || res.Header().Get("Concurrency-Remaining") != "0" {
+		t.Fatalf("expected the concurrency headers, got %v", res.Header())
+	}
+}
+
+func TestServerOnlyCountsRequestsReachingTheHandler(t *testing.T) {
+	server := scraperapitest.NewServer(scraperapitest.WithConcurrencyLimit(1))
+	defer server.Close()
+
+	server.Script("https://example.com",
+		scraperapitest.Response{Body: "first", Delay: 200 * time.Millisecond},
+		scraperapitest.Response{Body: "second"},
+		scraperapitest.Response{Body: "third"},
+	)
+	client := server.NewClient(scraperapi.WithMaxRetryCount(0))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = client.Get(context.Background(), "https://example.com", nil)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	res, err := client.Get(context.Background(), "https://example.com", nil)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.StatusCode() != http.StatusTooManyRequests {
+		t.Fatalf("expected a 429, got %d", res.StatusCode())
+	}
+
+	res, err = client.Get(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.String() != "second" {
+		t.Fatalf("expected the rejected request not to use up a scripted response, got %q", res.String())
+	}
+	if requests := server.Requests(); len(requests) != 3 || requests[1].N != 0 || requests[2].N != 2 {
+		t.Fatalf("expected the rejected request not to be counted, got %+v", requests)
+	}
+}
+
+func TestServerChargesFromTheCostTableOfTheClient(t *testing.T) {
+	server := scraperapitest.NewServer()
+	defer server.Close()
+	client := server.NewClient()
+
+	for _, params := range []*scraperapi.RequestParameters{
+		nil,
+		{JSRender: true, UsePremiumProxies: true},
+		{Mode: scraperapi.ModeAuto},
+	} {
+		res, err := client.Get(context.Background(), "https://example.com", params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := float64(scraperapi.EstimateCost(params, 1).Min); res.Cost().Credits != want {
+			t.Fatalf("expected %g credits for %+v, got %g", want, params, res.Cost().Credits)
+		}
+	}
+
+	// ModeAuto is charged for the configuration Adaptive Stealth Mode ends up using
+	server.Script("https://example.com/protected", scraperapitest.Response{Body: "ok", AutoTier: scraperapi.TierJSAndPremium})
+	res, err := client.Get(context.Background(), "https://example.com/protected", &scraperapi.RequestParameters{Mode: scraperapi.ModeAuto})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cost := res.Cost(); cost.Credits != scraperapi.JSAndProxyCredits || cost.Tier != scraperapi.TierJSAndPremium {
+		t.Fatalf("expected ModeAuto to be charged for the js_render+premium_proxy tier, got %+v", cost)
+	}
+}
+
+func TestServerChargesAllowedStatusCodes(t *testing.T) {
+	server := scraperapitest.NewServer()
+	defer server.Close()
+	server.Handle("https://example.com/missing", func(*scraperapitest.Request) scraperapitest.Response {
+		return scraperapitest.Response{Status: http.StatusNotFound, Body: "not found"}
+	})
+	client := server.NewClient(scraperapi.WithBudget(100))
+
+	res, err := client.Get(context.Background(), "https://example.com/missing", &scraperapi.RequestParameters{JSRender: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Cost().Reported {
+		t.Fatalf("expected a 404 not to be charged, got %+v", res.Cost())
+	}
+
+	params := &scraperapi.RequestParameters{JSRender: true, AllowedStatusCodes: []int{http.StatusNotFound}}
+	if res, err = client.Get(context.Background(), "https://example.com/missing", params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Cost().Credits != scraperapi.JSCredits {
+		t.Fatalf("expected the allowed 404 to be charged %d credits, got %+v", scraperapi.JSCredits, res.Cost())
+	}
+	if spent := client.Budget().Spent(); spent != scraperapi.JSCredits {
+		t.Fatalf("expected the budget to be charged for the allowed 404 only, got %g", spent)
+	}
+}
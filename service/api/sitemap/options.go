@@ -0,0 +1,79 @@
+package sitemap
+
+import (
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+const defaultMaxSitemaps = 100
+
+// Option configures the Reader.
+type Option interface {
+	apply(*options)
+}
+
+// options holds the configuration for the Reader
+type options struct {
+	// maxURLs is the maximum number of URLs yielded by a read. Defaults to 0 (no limit).
+	maxURLs int
+	// maxSitemaps is the maximum number of sitemaps fetched by a read, indexes included. 0 means no limit. Defaults to 100.
+	maxSitemaps int
+	// modifiedSince, if set, skips the URLs and sitemaps whose lastmod is before it.
+	modifiedSince time.Time
+	// params are the request parameters sitemaps are fetched with. Defaults to none.
+	params *scraperapi.RequestParameters
+}
+
+// defaultOptions returns the default options for the Reader.
+func defaultOptions() options {
+	return options{
+		maxSitemaps: defaultMaxSitemaps,
+	}
+}
+
+// funcOption wraps a function that modifies options into an implementation of the Option interface.
+type funcOption struct {
+	f func(*options)
+}
+
+func (fo *funcOption) apply(o *options) {
+	fo.f(o)
+}
+
+func newFuncOption(f func(*options)) *funcOption {
+	return &funcOption{
+		f: f,
+	}
+}
+
+// WithMaxURLs returns an Option which configures the maximum number of URLs yielded by a read. Defaults to 0 (no limit).
+func WithMaxURLs(maxURLs int) Option {
+	return newFuncOption(func(o *options) {
+		o.maxURLs = maxURLs
+	})
+}
+
+// WithMaxSitemaps returns an Option which configures the maximum number of sitemaps fetched by a read, sitemap indexes included.
+// Every sitemap is a billed request. 0 means no limit. Defaults to 100.
+func WithMaxSitemaps(maxSitemaps int) Option {
+	return newFuncOption(func(o *options) {
+		o.maxSitemaps = maxSitemaps
+	})
+}
+
+// WithModifiedSince returns an Option which skips the URLs whose lastmod is before since. The sitemaps of an index whose lastmod is
+// before since are not fetched at all, as none of their URLs can be more recent. Entries without a lastmod are always kept.
+func WithModifiedSince(since time.Time) Option {
+	return newFuncOption(func(o *options) {
+		o.modifiedSince = since
+	})
+}
+
+// WithParams returns an Option which configures the request parameters sitemaps are fetched with, e.g. UsePremiumProxies for
+// sites that block sitemap requests.
+func WithParams(params *scraperapi.RequestParameters) Option {
+	return newFuncOption(func(o *options) {
+		o.params = params
+	})
+}
@@ -0,0 +1,291 @@
+// Package sitemap reads sitemaps and sitemap indexes (https://www.sitemaps.org/protocol.html) through the ZenRows Fetch API, so
+// sitemaps behind anti-bot protections can be read too. The URLs it returns can be used as crawl seeds, or turned into Batch API
+// tasks with batch.TasksFromURLs.
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+// maxSitemapSize is the maximum uncompressed size of a sitemap, as set by the sitemap protocol.
+const maxSitemapSize = 50 * 1024 * 1024
+
+// lastModLayouts are the W3C Datetime layouts allowed for lastmod.
+var lastModLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04Z07:00", "2006-01-02"}
+
+// Client is the subset of scraperapi.Client used by a Reader, so it can be fed a fake in tests. scraperapi.Client, any
+// scraperapi.IClient and scraperapitest.MockClient implement it.
+type Client interface {
+	// Get sends a GET request to the ZenRows Fetch API to scrape the given target URL (see scraperapi.Client.Get).
+	Get(ctx context.Context, targetURL string, params *scraperapi.RequestParameters) (*scraperapi.Response, error)
+}
+
+// scraperapi.Client must implement Client.
+var _ Client = (*scraperapi.Client)(nil)
+
+// Reader reads sitemaps through the ZenRows Fetch API. It is safe for concurrent use.
+type Reader struct {
+	client Client
+	cfg    options
+}
+
+// URL is a URL listed in a sitemap.
+type URL struct {
+	// Loc is the URL of the page.
+	Loc string
+	// LastMod is when the page was last modified. It is zero if the sitemap does not say, or says it in an invalid format.
+	LastMod time.Time
+	// ChangeFreq is how frequently the page is likely to change, e.g. "daily". It is empty if the sitemap does not say.
+	ChangeFreq string
+	// Priority is the priority of the page relative to the other pages of the site, between 0 and 1. It is 0 if the sitemap does not
+	// say.
+	Priority float64
+	// Sitemap is the URL of the sitemap that lists the page.
+	Sitemap string
+}
+
+// String returns the URL of the page.
+func (u URL) String() string {
+	return u.Loc
+}
+
+// Error results when a sitemap cannot be fetched or parsed.
+type Error struct {
+	// Sitemap is the URL of the sitemap.
+	Sitemap string
+	// StatusCode is the status code of the response, if any.
+	StatusCode int
+	Err        error
+}
+
+func (e Error) Unwrap() error {
+	return e.Err
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("sitemap %s: %v", e.Sitemap, e.Err)
+}
+
+// NewReader creates and returns a new Reader that fetches sitemaps with the given client.
+func NewReader(client Client, opts ...Option) *Reader {
+	reader := &Reader{client: client, cfg: defaultOptions()}
+
+	for _, opt := range opts {
+		opt.apply(&reader.cfg)
+	}
+
+	return reader
+}
+
+// Read reads the given sitemaps, following the sitemaps listed by sitemap indexes, and yields every URL they list once. Sitemaps
+// compressed with gzip (e.g. sitemap.xml.gz) are decompressed.
+//
+// When a sitemap cannot be fetched or parsed, an Error is yielded along with a URL whose only Sitemap field is set, and the read
+// goes on with the next sitemap.
+func (r *Reader) Read(ctx context.Context, sitemapURLs ...string) iter.Seq2[URL, error] {
+	return func(yield func(URL, error) bool) {
+		var (
+			queue   = append([]string(nil), sitemapURLs...)
+			visited = make(map[string]struct{})
+			seen    = make(map[string]struct{})
+			fetched int
+			yielded int
+		)
+
+		for len(queue) > 0 && (r.cfg.maxSitemaps <= 0 || fetched < r.cfg.maxSitemaps) {
+			sitemapURL := queue[0]
+			queue = queue[1:]
+			if _, dup := visited[sitemapURL]; dup {
+				continue
+			}
+			visited[sitemapURL] = struct{}{}
+			fetched++
+
+			doc, err := r.fetch(ctx, sitemapURL)
+			if err != nil {
+				if !yield(URL{Sitemap: sitemapURL}, err) || ctx.Err() != nil {
+					return
+				}
+				continue
+			}
+
+			for _, entry := range doc.Sitemaps {
+				if r.modifiedSince(parseLastMod(entry.LastMod)) {
+					queue = append(queue, strings.TrimSpace(entry.Loc))
+				}
+			}
+
+			for _, entry := range doc.URLs {
+				u := entry.toURL(sitemapURL)
+				if _, dup := seen[u.Loc]; dup || u.Loc == "" || !r.modifiedSince(u.LastMod) {
+					continue
+				}
+				seen[u.Loc] = struct{}{}
+
+				if !yield(u, nil) {
+					return
+				}
+				if yielded++; r.cfg.maxURLs > 0 && yielded >= r.cfg.maxURLs {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Locations reads the given sitemaps like Read, and returns the URL of every page they list, ready to be used as crawl seeds or
+// turned into Batch API tasks. The errors of the sitemaps that could not be read are joined in the returned error, along with the
+// URLs of those that could.
+func (r *Reader) Locations(ctx context.Context, sitemapURLs ...string) ([]string, error) {
+	var (
+		locations []string
+		errs      []error
+	)
+
+	for u, err := range r.Read(ctx, sitemapURLs...) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		locations = append(locations, u.Loc)
+	}
+
+	return locations, errors.Join(errs...)
+}
+
+// Discover returns the sitemaps of the site siteURL belongs to, as listed by the Sitemap directives of its robots.txt. If the
+// robots.txt cannot be fetched or lists no sitemap, it returns the conventional /sitemap.xml of the site.
+func (r *Reader) Discover(ctx context.Context, siteURL string) ([]string, error) {
+	site, err := url.Parse(siteURL)
+	if err != nil || site.Scheme == "" || site.Host == "" {
+		return nil, scraperapi.InvalidTargetURLError{URL: siteURL, Msg: "invalid site url", Err: err}
+	}
+	origin := site.Scheme + "://" + site.Host
+
+	response, err := r.client.Get(ctx, origin+"/robots.txt", r.cfg.params)
+	if err != nil {
+		return nil, err
+	}
+
+	var sitemaps []string
+	if response.IsSuccess() {
+		scanner := bufio.NewScanner(bytes.NewReader(response.Body()))
+		for scanner.Scan() {
+			key, value, ok := strings.Cut(scanner.Text(), ":")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "sitemap") {
+				sitemaps = append(sitemaps, strings.TrimSpace(value))
+			}
+		}
+	}
+
+	if len(sitemaps) == 0 {
+		sitemaps = append(sitemaps, origin+"/sitemap.xml")
+	}
+	return sitemaps, nil
+}
+
+// modifiedSince reports whether an entry with the given lastmod passes the WithModifiedSince filter.
+func (r *Reader) modifiedSince(lastMod time.Time) bool {
+	return r.cfg.modifiedSince.IsZero() || lastMod.IsZero() || !lastMod.Before(r.cfg.modifiedSince)
+}
+
+// document is a sitemap (a urlset) or a sitemap index. Only one of its fields is set.
+type document struct {
+	URLs     []urlEntry     `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type urlEntry struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod"`
+	ChangeFreq string `xml:"changefreq"`
+	Priority   string `xml:"priority"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// toURL converts the entry into a URL listed by sitemapURL.
+func (e urlEntry) toURL(sitemapURL string) URL {
+	priority, _ := strconv.ParseFloat(strings.TrimSpace(e.Priority), 64)
+	return URL{
+		Loc:        strings.TrimSpace(e.Loc),
+		LastMod:    parseLastMod(e.LastMod),
+		ChangeFreq: strings.TrimSpace(e.ChangeFreq),
+		Priority:   priority,
+		Sitemap:    sitemapURL,
+	}
+}
+
+// fetch fetches and parses a sitemap.
+func (r *Reader) fetch(ctx context.Context, sitemapURL string) (*document, error) {
+	response, err := r.client.Get(ctx, sitemapURL, r.cfg.params)
+	if err != nil {
+		return nil, Error{Sitemap: sitemapURL, Err: err}
+	}
+
+	if response.IsError() {
+		return nil, Error{Sitemap: sitemapURL, StatusCode: response.StatusCode(), Err: response.Error()}
+	}
+
+	doc, err := parse(response.Body())
+	if err != nil {
+		return nil, Error{Sitemap: sitemapURL, StatusCode: response.StatusCode(), Err: err}
+	}
+	return doc, nil
+}
+
+// parse parses a sitemap or a sitemap index, decompressing it first if it is compressed with gzip.
+func parse(data []byte) (*document, error) {
+	var body io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	// read one byte past the limit, so an oversized sitemap is detected instead of silently truncated
+	data, err := io.ReadAll(io.LimitReader(body, maxSitemapSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxSitemapSize {
+		return nil, fmt.Errorf("sitemap is larger than %d bytes", maxSitemapSize)
+	}
+
+	var doc document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// parseLastMod parses a lastmod value, returning the zero time if it is empty or invalid.
+func parseLastMod(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range lastModLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,177 @@
+package sitemap_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+	"github.com/zenrows/zenrows-go-sdk/service/api/scraperapitest"
+	"github.com/zenrows/zenrows-go-sdk/service/api/sitemap"
+)
+
+// fakeAPI is a fake ZenRows Fetch API serving the given bodies, keyed by target url. It records the targets it is asked for.
+type fakeAPI struct {
+	bodies map[string][]byte
+
+	mu      sync.Mutex
+	targets []string
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	target := r.URL.Query().Get("url")
+	f.mu.Lock()
+	f.targets = append(f.targets, target)
+	f.mu.Unlock()
+
+	body, ok := f.bodies[target]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+func newReader(t *testing.T, api *fakeAPI, opts ...sitemap.Option) *sitemap.Reader {
+	t.Helper()
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	return sitemap.NewReader(scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k")), opts...)
+}
+
+func gzipped(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testSite(t *testing.T) map[string][]byte {
+	return map[string][]byte{
+		"https://example.com/sitemap_index.xml": []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<sitemap><loc>https://example.com/pages.xml</loc><lastmod>2026-05-01</lastmod></sitemap>
+	<sitemap><loc>https://example.com/posts.xml.gz</loc><lastmod>2026-09-01T10:00:00+00:00</lastmod></sitemap>
+	<sitemap><loc>https://example.com/missing.xml</loc></sitemap>
+</sitemapindex>`),
+		"https://example.com/pages.xml": []byte(`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc>https://example.com/</loc><lastmod>2026-05-01</lastmod><priority>1.0</priority></url>
+	<url><loc>https://example.com/about</loc></url>
+</urlset>`),
+		"https://example.com/posts.xml.gz": gzipped(t, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+	<url><loc> https://example.com/posts/1 </loc><lastmod>2026-08-01T10:00:00Z</lastmod><changefreq>daily</changefreq></url>
+	<url><loc>https://example.com/posts/2</loc><lastmod>2026-09-01T10:00:00Z</lastmod></url>
+	<url><loc>https://example.com/</loc></url>
+</urlset>`),
+	}
+}
+
+func TestReadFollowsIndexesAndDecompressesGzip(t *testing.T) {
+	reader := newReader(t, &fakeAPI{bodies: testSite(t)})
+
+	var (
+		urls []sitemap.URL
+		errs []error
+	)
+	for u, err := range reader.Read(context.Background(), "https://example.com/sitemap_index.xml") {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		urls = append(urls, u)
+	}
+
+	var locs []string
+	for _, u := range urls {
+		locs = append(locs, u.String())
+	}
+	want := []string{"https://example.com/", "https://example.com/about", "https://example.com/posts/1", "https://example.com/posts/2"}
+	if !slices.Equal(locs, want) {
+		t.Fatalf("expected every url to be read once, got %v", locs)
+	}
+
+	first, wantLastMod := urls[0], time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	if first.Priority != 1 || !first.LastMod.Equal(wantLastMod) || first.Sitemap != "https://example.com/pages.xml" {
+		t.Fatalf("unexpected url: %+v", first)
+	}
+	if urls[2].ChangeFreq != "daily" {
+		t.Fatalf("unexpected changefreq: %q", urls[2].ChangeFreq)
+	}
+
+	var sitemapErr sitemap.Error
+	if len(errs) != 1 || !errors.As(errs[0], &sitemapErr) || sitemapErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a single error for the missing sitemap, got %v", errs)
+	}
+}
+
+func TestReadFiltersByLastModAndLimitsURLs(t *testing.T) {
+	api := &fakeAPI{bodies: testSite(t)}
+	since := time.Date(2026, 8, 15, 0, 0, 0, 0, time.UTC)
+	reader := newReader(t, api, sitemap.WithModifiedSince(since), sitemap.WithMaxURLs(2))
+
+	locs, err := reader.Locations(context.Background(), "https://example.com/sitemap_index.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// posts/1 is too old, and the / entry of posts.xml.gz has no lastmod; the limit stops the read before missing.xml is fetched
+	if want := []string{"https://example.com/posts/2", "https://example.com/"}; !slices.Equal(locs, want) {
+		t.Fatalf("got %v, want %v", locs, want)
+	}
+	if slices.Contains(api.targets, "https://example.com/pages.xml") {
+		t.Fatal("expected the sitemap last modified before the filter not to be fetched")
+	}
+}
+
+func TestDiscoverReadsRobotsTxt(t *testing.T) {
+	reader := newReader(t, &fakeAPI{bodies: map[string][]byte{
+		"https://example.com/robots.txt": []byte("User-agent: *\nDisallow: /admin\nSitemap: https://example.com/sitemap_index.xml\n"),
+	}})
+
+	sitemaps, err := reader.Discover(context.Background(), "https://example.com/some/page")
+	if err != nil || !slices.Equal(sitemaps, []string{"https://example.com/sitemap_index.xml"}) {
+		t.Fatalf("unexpected sitemaps: %v (%v)", sitemaps, err)
+	}
+
+	sitemaps, err = reader.Discover(context.Background(), "https://other.com")
+	if err != nil || !slices.Equal(sitemaps, []string{"https://other.com/sitemap.xml"}) {
+		t.Fatalf("expected the conventional sitemap when robots.txt is missing, got %v (%v)", sitemaps, err)
+	}
+}
+
+func TestReadAcceptsMockClient(t *testing.T) {
+	bodies := testSite(t)
+	mock := &scraperapitest.MockClient{
+		ScrapeFunc: func(_ context.Context, _, targetURL string, _ *scraperapi.RequestParameters, _ any) (*scraperapi.Response, error) {
+			body, ok := bodies[targetURL]
+			if !ok {
+				return scraperapi.NewResponse(http.StatusNotFound, nil, nil), nil
+			}
+			return scraperapi.NewResponse(http.StatusOK, nil, body), nil
+		},
+	}
+
+	locations, err := sitemap.NewReader(mock).Locations(context.Background(), "https://example.com/pages.xml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(locations, []string{"https://example.com/", "https://example.com/about"}) {
+		t.Fatalf("unexpected locations %v", locations)
+	}
+}
@@ -0,0 +1,93 @@
+package scraperapi
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+type tagContextKey struct{}
+
+// ContextWithTag returns a copy of ctx that sends requests with the given tag, so their spend is aggregated under it by the spend
+// meter of the client (see Client.Spend).
+func ContextWithTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, tagContextKey{}, tag)
+}
+
+// tagFromContext returns the tag set on ctx with ContextWithTag, or an empty string.
+func tagFromContext(ctx context.Context) string {
+	tag, _ := ctx.Value(tagContextKey{}).(string)
+	return tag
+}
+
+// SpendTotal is the number of requests and the credits they were charged.
+type SpendTotal struct {
+	Requests int
+	Credits  float64
+}
+
+func (t SpendTotal) add(credits float64) SpendTotal {
+	return SpendTotal{Requests: t.Requests + 1, Credits: t.Credits + credits}
+}
+
+// SpendSnapshot is the spend of a client, in total and broken down by tag, response status code and pricing tier.
+type SpendSnapshot struct {
+	Total SpendTotal
+	// ByTag is keyed by the tag requests were sent with (see ContextWithTag). Requests sent without a tag are under "".
+	ByTag map[string]SpendTotal
+	// ByStatus is keyed by the response status code.
+	ByStatus map[int]SpendTotal
+	// ByTier is keyed by the configuration requests were charged for (see Cost.Tier).
+	ByTier map[Tier]SpendTotal
+}
+
+// SpendMeter aggregates the credits charged for the requests of a client, as reported by their responses (see Response.Cost). It
+// is safe for concurrent use.
+type SpendMeter struct {
+	mu       sync.Mutex
+	snapshot SpendSnapshot
+}
+
+func newSpendMeter() *SpendMeter {
+	m := &SpendMeter{}
+	m.Reset()
+	return m
+}
+
+// Snapshot returns a copy of the spend aggregated so far.
+func (m *SpendMeter) Snapshot() SpendSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return SpendSnapshot{
+		Total:    m.snapshot.Total,
+		ByTag:    maps.Clone(m.snapshot.ByTag),
+		ByStatus: maps.Clone(m.snapshot.ByStatus),
+		ByTier:   maps.Clone(m.snapshot.ByTier),
+	}
+}
+
+// Reset clears the spend aggregated so far.
+func (m *SpendMeter) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshot = SpendSnapshot{
+		ByTag:    make(map[string]SpendTotal),
+		ByStatus: make(map[int]SpendTotal),
+		ByTier:   make(map[Tier]SpendTotal),
+	}
+}
+
+// record adds the cost of a response to the meter.
+func (m *SpendMeter) record(tag string, response *Response) {
+	cost := response.Cost()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshot.Total = m.snapshot.Total.add(cost.Credits)
+	m.snapshot.ByTag[tag] = m.snapshot.ByTag[tag].add(cost.Credits)
+	m.snapshot.ByStatus[response.StatusCode()] = m.snapshot.ByStatus[response.StatusCode()].add(cost.Credits)
+	m.snapshot.ByTier[cost.Tier] = m.snapshot.ByTier[cost.Tier].add(cost.Credits)
+}
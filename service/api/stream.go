@@ -0,0 +1,102 @@
+package scraperapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxStreamErrorBodySize caps how much of an error response body ScrapeStream buffers so Problem and Error keep working.
+const maxStreamErrorBodySize = 64 * 1024
+
+// ScrapeStream works like Scrape, but does not buffer the response body: read it from Response.BodyReader instead, and Close the
+// response when done. Use it for PDFs (ResponseTypePDF) and large pages, to keep memory bounded.
+//
+// The concurrency slot acquired for the request (see WithMaxConcurrentRequests) is held until the response is closed, so memory
+// stays bounded under high concurrency too. Error responses (IsError) are buffered and closed right away, so Problem and Error
+// work as usual and the slot is released immediately.
+//
+// IMPORTANT: always Close the returned response, otherwise both the connection and the concurrency slot leak.
+func (c *Client) ScrapeStream(ctx context.Context, method, targetURL string, params *RequestParameters, body any) (*Response, error) {
+	return c.handler(ctx, &Request{Method: method, TargetURL: targetURL, Params: params, Body: body, Stream: true})
+}
+
+// streamResponse makes the body of a successful response a streamBody, which releases the concurrency slot of the request when
+// closed.
+func (c *Client) streamResponse(response *Response, release func()) (*Response, error) {
+	stream := &streamBody{rc: response.res.RawBody(), limit: c.cfg.maxStreamBodySize, remaining: c.cfg.maxStreamBodySize, release: release}
+	if stream.rc == nil {
+		stream.rc = http.NoBody
+	}
+
+	if stream.limit > 0 && response.res.RawResponse != nil && response.res.RawResponse.ContentLength > stream.limit {
+		_ = stream.Close()
+		return nil, BodyTooLargeError{Limit: stream.limit}
+	}
+
+	response.stream = stream
+	return response, nil
+}
+
+// GetStream sends an HTTP GET request like Get, streaming the response body. See ScrapeStream.
+func (c *Client) GetStream(ctx context.Context, targetURL string, params *RequestParameters) (*Response, error) {
+	return c.ScrapeStream(ctx, http.MethodGet, targetURL, params, nil)
+}
+
+// BodyReader method returns the response body as a stream. For responses returned by Client.ScrapeStream, this is the only way to
+// read the body, and it can only be read once; for buffered responses, it reads from Body.
+func (r *Response) BodyReader() io.ReadCloser {
+	if r.stream != nil {
+		return r.stream
+	}
+	return io.NopCloser(bytes.NewReader(r.Body()))
+}
+
+// Close method closes the body of a response returned by Client.ScrapeStream and releases its concurrency slot. It is safe to call
+// more than once, and a no-op for buffered responses.
+func (r *Response) Close() error {
+	if r.stream == nil {
+		return nil
+	}
+	return r.stream.Close()
+}
+
+// streamBody is a streamed response body that enforces the size cap and releases the concurrency slot on Close.
+type streamBody struct {
+	rc        io.ReadCloser
+	limit     int64
+	remaining int64
+	release   func()
+
+	once     sync.Once
+	closeErr error
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.rc.Read(p)
+	}
+
+	// read one byte past the limit, so an oversized body is detected instead of silently truncated
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.rc.Read(p)
+	if int64(n) > b.remaining {
+		n = int(b.remaining)
+		b.remaining = 0
+		return n, BodyTooLargeError{Limit: b.limit}
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
+
+func (b *streamBody) Close() error {
+	b.once.Do(func() {
+		b.closeErr = b.rc.Close()
+		b.release()
+	})
+	return b.closeErr
+}
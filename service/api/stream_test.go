@@ -0,0 +1,136 @@
+package scraperapi_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	scraperapi "github.com/zenrows/zenrows-go-sdk/service/api"
+)
+
+func TestGetStreamExposesBodyAsReader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write([]byte("%PDF-1.7 streamed"))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"))
+	res, err := client.GetStream(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Close()
+
+	if len(res.Body()) != 0 {
+		t.Fatal("expected the body not to be buffered")
+	}
+	data, err := io.ReadAll(res.BodyReader())
+	if err != nil || string(data) != "%PDF-1.7 streamed" {
+		t.Fatalf("unexpected streamed body %q (%v)", data, err)
+	}
+	if err := res.Close(); err != nil {
+		t.Fatalf("expected Close to be idempotent, got %v", err)
+	}
+}
+
+func TestGetStreamEnforcesMaxBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// flush before writing everything, so the body is chunked and has no Content-Length
+		_, _ = w.Write([]byte(strings.Repeat("a", 8)))
+		w.(http.Flusher).Flush()
+		_, _ = w.Write([]byte(strings.Repeat("b", 8)))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithMaxStreamBodySize(10))
+	res, err := client.GetStream(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer res.Close()
+
+	data, err := io.ReadAll(res.BodyReader())
+	var tooLarge scraperapi.BodyTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 10 {
+		t.Fatalf("expected BodyTooLargeError, got %v", err)
+	}
+	if len(data) != 10 {
+		t.Fatalf("expected exactly the allowed bytes to be read, got %d", len(data))
+	}
+}
+
+func TestGetStreamRejectsOversizedContentLengthUpFront(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(strings.Repeat("a", 64)))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithMaxStreamBodySize(10))
+	var tooLarge scraperapi.BodyTooLargeError
+	if _, err := client.GetStream(context.Background(), "https://example.com", nil); !errors.As(err, &tooLarge) {
+		t.Fatalf("expected BodyTooLargeError, got %v", err)
+	}
+}
+
+func TestGetStreamHoldsConcurrencySlotUntilClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithMaxConcurrentRequests(1))
+	first, err := client.GetStream(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = client.Get(context.Background(), "https://example.com", nil)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("expected the second request to wait for the streamed response to be closed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	_ = first.Close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected the second request to proceed once the streamed response was closed")
+	}
+}
+
+func TestGetStreamBuffersErrorResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"title":"Could not get content","status":422,"code":"RESP001"}`))
+	}))
+	defer server.Close()
+
+	client := scraperapi.NewClient(scraperapi.WithBaseURL(server.URL), scraperapi.WithAPIKey("k"), scraperapi.WithMaxConcurrentRequests(1))
+	res, err := client.GetStream(context.Background(), "https://example.com", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prob := res.Problem(); prob == nil || prob.Code != "RESP001" {
+		t.Fatalf("expected the problem to be parsed from the buffered body, got %+v", prob)
+	}
+
+	// the slot was released without closing the response, so this must not block
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := client.Get(ctx, "https://example.com", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
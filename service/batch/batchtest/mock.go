@@ -0,0 +1,380 @@
+package batchtest
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/zenrows/zenrows-go-sdk/service/batch"
+)
+
+// ErrNotMocked is returned by the methods of a MockClient without a function to answer them.
+var ErrNotMocked = errors.New("batchtest: method not mocked")
+
+// Call is a call to a method of a MockClient.
+type Call struct {
+	// Name is the name of the method called, e.g. "SubmitJob".
+	Name string
+	// Args are the arguments of the call, without the context.
+	Args []any
+}
+
+// MockClient is a fake batch.IClient, to inject into code under test instead of a
+// batch.Client. Every method answers with the function of the same name, e.g.
+// SubmitJobFunc, and records the call. If the function is nil, the method returns
+// ErrNotMocked, the sequences of the Iter methods yield it, and EstimateCost falls back to
+// batch.EstimateCost. It is safe for concurrent use, as long as the functions are set
+// before it is used.
+type MockClient struct {
+	SubmitJobFunc          func(ctx context.Context, jobReq batch.SubmitJobRequest) (*batch.SubmitJobResponse, error)
+	ListJobsFunc           func(ctx context.Context, opts batch.ListJobsOptions) (*batch.ListJobsResponse, error)
+	IterJobsFunc           func(ctx context.Context, opts batch.ListJobsOptions) iter.Seq2[batch.Job, error]
+	DeleteJobFunc          func(ctx context.Context, jobID string) error
+	AddTasksFunc           func(ctx context.Context, jobID string, tasks []batch.Task, lastBatch bool) (*batch.AddTasksResponse, error)
+	CloseJobFunc           func(ctx context.Context, jobID string) (*batch.Job, error)
+	StopRunFunc            func(ctx context.Context, jobID string) (*batch.Run, error)
+	RerunFunc              func(ctx context.Context, jobID string, opts batch.RerunOptions) (*batch.RerunJobResponse, error)
+	ListRunsFunc           func(ctx context.Context, jobID string, opts batch.ListRunsOptions) (*batch.ListRunsResponse, error)
+	IterRunsFunc           func(ctx context.Context, jobID string, opts batch.ListRunsOptions) iter.Seq2[batch.Run, error]
+	DeleteRunFunc          func(ctx context.Context, jobID, runID string) error
+	GetResultsFunc         func(ctx context.Context, jobID string, opts batch.GetResultsOptions) (*batch.GetResultsResponse, error)
+	IterResultsFunc        func(ctx context.Context, jobID string, opts batch.GetResultsOptions) iter.Seq2[batch.TaskResult, error]
+	GetTaskContentFunc     func(ctx context.Context, jobID, taskID string, opts batch.GetTaskContentOptions) ([]byte, error)
+	GetTaskHistoryFunc     func(ctx context.Context, jobID, taskID, runID string) (*batch.TaskHistoryResponse, error)
+	WaitForRunFunc         func(ctx context.Context, jobID string, opts batch.WaitForRunOptions) (batch.Run, error)
+	WaitForIngestFunc      func(ctx context.Context, jobID string, opts batch.WaitForIngestOptions) (batch.Job, error)
+	DownloadToDirFunc      func(ctx context.Context, jobID, targetDir string, opts batch.DownloadToDirOptions) (int, error)
+	DownloadToMemoryFunc   func(ctx context.Context, jobID string, opts batch.DownloadToMemoryOptions) ([]batch.DownloadedResult, error)
+	WaitForExportFunc      func(ctx context.Context, jobID, runID, exportID string, opts batch.WaitForExportOptions) (batch.Export, error)
+	DownloadAllResultsFunc func(ctx context.Context, jobID, runID, targetPath string, opts batch.DownloadAllResultsOptions) (string, error)
+	CreateJobInputFunc     func(ctx context.Context, req batch.CreateJobInputRequest) (*batch.CreateJobInputResponse, error)
+	UploadCSVFunc          func(ctx context.Context, source any, opts batch.UploadCSVOptions) (string, error)
+	GetJobWebhookFunc      func(ctx context.Context, jobID string) (*batch.WebhookConfig, error)
+	PutJobWebhookFunc      func(ctx context.Context, jobID string, config batch.WebhookConfig) (*batch.WebhookConfig, error)
+	DeleteJobWebhookFunc   func(ctx context.Context, jobID string) error
+	TestWebhookFunc        func(ctx context.Context, req batch.TestWebhookRequest) (*batch.TestWebhookResponse, error)
+	ListHMACKeysFunc       func(ctx context.Context) (*batch.HMACKeyList, error)
+	RotateHMACKeyFunc      func(ctx context.Context) (*batch.HMACKeyCreated, error)
+	FinalizeHMACKeyFunc    func(ctx context.Context) (*batch.HMACKeyFinalized, error)
+	CancelHMACRotationFunc func(ctx context.Context) error
+	EstimateCostFunc       func(tasks []batch.Task, zenrowsParams map[string]any) batch.CostEstimate
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// MockClient must implement batch.IClient.
+var _ batch.IClient = (*MockClient)(nil)
+
+// Calls returns the calls made so far, in order.
+func (m *MockClient) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slices.Clone(m.calls)
+}
+
+// record records a call.
+func (m *MockClient) record(name string, args ...any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Name: name, Args: args})
+}
+
+// SubmitJob implements batch.IClient.
+func (m *MockClient) SubmitJob(ctx context.Context, jobReq batch.SubmitJobRequest) (*batch.SubmitJobResponse, error) {
+	m.record("SubmitJob", jobReq)
+	if m.SubmitJobFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.SubmitJobFunc(ctx, jobReq)
+}
+
+// ListJobs implements batch.IClient.
+func (m *MockClient) ListJobs(ctx context.Context, opts batch.ListJobsOptions) (*batch.ListJobsResponse, error) {
+	m.record("ListJobs", opts)
+	if m.ListJobsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ListJobsFunc(ctx, opts)
+}
+
+// IterJobs implements batch.IClient. If IterJobsFunc is nil, the sequence yields ErrNotMocked.
+func (m *MockClient) IterJobs(ctx context.Context, opts batch.ListJobsOptions) iter.Seq2[batch.Job, error] {
+	m.record("IterJobs", opts)
+	if m.IterJobsFunc == nil {
+		return func(yield func(batch.Job, error) bool) {
+			yield(batch.Job{}, ErrNotMocked)
+		}
+	}
+	return m.IterJobsFunc(ctx, opts)
+}
+
+// DeleteJob implements batch.IClient.
+func (m *MockClient) DeleteJob(ctx context.Context, jobID string) error {
+	m.record("DeleteJob", jobID)
+	if m.DeleteJobFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteJobFunc(ctx, jobID)
+}
+
+// AddTasks implements batch.IClient.
+func (m *MockClient) AddTasks(ctx context.Context, jobID string, tasks []batch.Task, lastBatch bool) (*batch.AddTasksResponse, error) {
+	m.record("AddTasks", jobID, tasks, lastBatch)
+	if m.AddTasksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.AddTasksFunc(ctx, jobID, tasks, lastBatch)
+}
+
+// CloseJob implements batch.IClient.
+func (m *MockClient) CloseJob(ctx context.Context, jobID string) (*batch.Job, error) {
+	m.record("CloseJob", jobID)
+	if m.CloseJobFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CloseJobFunc(ctx, jobID)
+}
+
+// StopRun implements batch.IClient.
+func (m *MockClient) StopRun(ctx context.Context, jobID string) (*batch.Run, error) {
+	m.record("StopRun", jobID)
+	if m.StopRunFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.StopRunFunc(ctx, jobID)
+}
+
+// Rerun implements batch.IClient.
+func (m *MockClient) Rerun(ctx context.Context, jobID string, opts batch.RerunOptions) (*batch.RerunJobResponse, error) {
+	m.record("Rerun", jobID, opts)
+	if m.RerunFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.RerunFunc(ctx, jobID, opts)
+}
+
+// ListRuns implements batch.IClient.
+func (m *MockClient) ListRuns(ctx context.Context, jobID string, opts batch.ListRunsOptions) (*batch.ListRunsResponse, error) {
+	m.record("ListRuns", jobID, opts)
+	if m.ListRunsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ListRunsFunc(ctx, jobID, opts)
+}
+
+// IterRuns implements batch.IClient. If IterRunsFunc is nil, the sequence yields ErrNotMocked.
+func (m *MockClient) IterRuns(ctx context.Context, jobID string, opts batch.ListRunsOptions) iter.Seq2[batch.Run, error] {
+	m.record("IterRuns", jobID, opts)
+	if m.IterRunsFunc == nil {
+		return func(yield func(batch.Run, error) bool) {
+			yield(batch.Run{}, ErrNotMocked)
+		}
+	}
+	return m.IterRunsFunc(ctx, jobID, opts)
+}
+
+// DeleteRun implements batch.IClient.
+func (m *MockClient) DeleteRun(ctx context.Context, jobID, runID string) error {
+	m.record("DeleteRun", jobID, runID)
+	if m.DeleteRunFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteRunFunc(ctx, jobID, runID)
+}
+
+// GetResults implements batch.IClient.
+func (m *MockClient) GetResults(ctx context.Context, jobID string, opts batch.GetResultsOptions) (*batch.GetResultsResponse, error) {
+	m.record("GetResults", jobID, opts)
+	if m.GetResultsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetResultsFunc(ctx, jobID, opts)
+}
+
+// IterResults implements batch.IClient. If IterResultsFunc is nil, the sequence yields ErrNotMocked.
+func (m *MockClient) IterResults(ctx context.Context, jobID string, opts batch.GetResultsOptions) iter.Seq2[batch.TaskResult, error] {
+	m.record("IterResults", jobID, opts)
+	if m.IterResultsFunc == nil {
+		return func(yield func(batch.TaskResult, error) bool) {
+			yield(batch.TaskResult{}, ErrNotMocked)
+		}
+	}
+	return m.IterResultsFunc(ctx, jobID, opts)
+}
+
+// GetTaskContent implements batch.IClient.
+func (m *MockClient) GetTaskContent(ctx context.Context, jobID, taskID string, opts batch.GetTaskContentOptions) ([]byte, error) {
+	m.record("GetTaskContent", jobID, taskID, opts)
+	if m.GetTaskContentFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetTaskContentFunc(ctx, jobID, taskID, opts)
+}
+
+// GetTaskHistory implements batch.IClient.
+func (m *MockClient) GetTaskHistory(ctx context.Context, jobID, taskID, runID string) (*batch.TaskHistoryResponse, error) {
+	m.record("GetTaskHistory", jobID, taskID, runID)
+	if m.GetTaskHistoryFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetTaskHistoryFunc(ctx, jobID, taskID, runID)
+}
+
+// WaitForRun implements batch.IClient.
+func (m *MockClient) WaitForRun(ctx context.Context, jobID string, opts batch.WaitForRunOptions) (batch.Run, error) {
+	m.record("WaitForRun", jobID, opts)
+	if m.WaitForRunFunc == nil {
+		return batch.Run{}, ErrNotMocked
+	}
+	return m.WaitForRunFunc(ctx, jobID, opts)
+}
+
+// WaitForIngest implements batch.IClient.
+func (m *MockClient) WaitForIngest(ctx context.Context, jobID string, opts batch.WaitForIngestOptions) (batch.Job, error) {
+	m.record("WaitForIngest", jobID, opts)
+	if m.WaitForIngestFunc == nil {
+		return batch.Job{}, ErrNotMocked
+	}
+	return m.WaitForIngestFunc(ctx, jobID, opts)
+}
+
+// DownloadToDir implements batch.IClient.
+func (m *MockClient) DownloadToDir(ctx context.Context, jobID, targetDir string, opts batch.DownloadToDirOptions) (int, error) {
+	m.record("DownloadToDir", jobID, targetDir, opts)
+	if m.DownloadToDirFunc == nil {
+		return 0, ErrNotMocked
+	}
+	return m.DownloadToDirFunc(ctx, jobID, targetDir, opts)
+}
+
+// DownloadToMemory implements batch.IClient.
+func (m *MockClient) DownloadToMemory(ctx context.Context, jobID string, opts batch.DownloadToMemoryOptions) ([]batch.DownloadedResult, error) {
+	m.record("DownloadToMemory", jobID, opts)
+	if m.DownloadToMemoryFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DownloadToMemoryFunc(ctx, jobID, opts)
+}
+
+// WaitForExport implements batch.IClient.
+func (m *MockClient) WaitForExport(ctx context.Context, jobID, runID, exportID string, opts batch.WaitForExportOptions) (batch.Export, error) {
+	m.record("WaitForExport", jobID, runID, exportID, opts)
+	if m.WaitForExportFunc == nil {
+		return batch.Export{}, ErrNotMocked
+	}
+	return m.WaitForExportFunc(ctx, jobID, runID, exportID, opts)
+}
+
+// DownloadAllResults implements batch.IClient.
+func (m *MockClient) DownloadAllResults(ctx context.Context, jobID, runID, targetPath string, opts batch.DownloadAllResultsOptions) (string, error) {
+	m.record("DownloadAllResults", jobID, runID, targetPath, opts)
+	if m.DownloadAllResultsFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.DownloadAllResultsFunc(ctx, jobID, runID, targetPath, opts)
+}
+
+// CreateJobInput implements batch.IClient.
+func (m *MockClient) CreateJobInput(ctx context.Context, req batch.CreateJobInputRequest) (*batch.CreateJobInputResponse, error) {
+	m.record("CreateJobInput", req)
+	if m.CreateJobInputFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.CreateJobInputFunc(ctx, req)
+}
+
+// UploadCSV implements batch.IClient.
+func (m *MockClient) UploadCSV(ctx context.Context, source any, opts batch.UploadCSVOptions) (string, error) {
+	m.record("UploadCSV", source, opts)
+	if m.UploadCSVFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.UploadCSVFunc(ctx, source, opts)
+}
+
+// GetJobWebhook implements batch.IClient.
+func (m *MockClient) GetJobWebhook(ctx context.Context, jobID string) (*batch.WebhookConfig, error) {
+	m.record("GetJobWebhook", jobID)
+	if m.GetJobWebhookFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetJobWebhookFunc(ctx, jobID)
+}
+
+// PutJobWebhook implements batch.IClient.
+func (m *MockClient) PutJobWebhook(ctx context.Context, jobID string, config batch.WebhookConfig) (*batch.WebhookConfig, error) {
+	m.record("PutJobWebhook", jobID, config)
+	if m.PutJobWebhookFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.PutJobWebhookFunc(ctx, jobID, config)
+}
+
+// DeleteJobWebhook implements batch.IClient.
+func (m *MockClient) DeleteJobWebhook(ctx context.Context, jobID string) error {
+	m.record("DeleteJobWebhook", jobID)
+	if m.DeleteJobWebhookFunc == nil {
+		return ErrNotMocked
+	}
+	return m.DeleteJobWebhookFunc(ctx, jobID)
+}
+
+// TestWebhook implements batch.IClient.
+func (m *MockClient) TestWebhook(ctx context.Context, req batch.TestWebhookRequest) (*batch.TestWebhookResponse, error) {
+	m.record("TestWebhook", req)
+	if m.TestWebhookFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.TestWebhookFunc(ctx, req)
+}
+
+// ListHMACKeys implements batch.IClient.
+func (m *MockClient) ListHMACKeys(ctx context.Context) (*batch.HMACKeyList, error) {
+	m.record("ListHMACKeys")
+	if m.ListHMACKeysFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ListHMACKeysFunc(ctx)
+}
+
+// RotateHMACKey implements batch.IClient.
+func (m *MockClient) RotateHMACKey(ctx context.Context) (*batch.HMACKeyCreated, error) {
+	m.record("RotateHMACKey")
+	if m.RotateHMACKeyFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.RotateHMACKeyFunc(ctx)
+}
+
+// FinalizeHMACKey implements batch.IClient.
+func (m *MockClient) FinalizeHMACKey(ctx context.Context) (*batch.HMACKeyFinalized, error) {
+	m.record("FinalizeHMACKey")
+	if m.FinalizeHMACKeyFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.FinalizeHMACKeyFunc(ctx)
+}
+
+// CancelHMACRotation implements batch.IClient.
+func (m *MockClient) CancelHMACRotation(ctx context.Context) error {
+	m.record("CancelHMACRotation")
+	if m.CancelHMACRotationFunc == nil {
+		return ErrNotMocked
+	}
+	return m.CancelHMACRotationFunc(ctx)
+}
+
+// EstimateCost implements batch.IClient. If EstimateCostFunc is nil, it returns the estimate
+// of batch.EstimateCost, which needs no network.
+func (m *MockClient) EstimateCost(tasks []batch.Task, zenrowsParams map[string]any) batch.CostEstimate {
+	m.record("EstimateCost", tasks, zenrowsParams)
+	if m.EstimateCostFunc == nil {
+		return batch.EstimateCost(tasks, zenrowsParams)
+	}
+	return m.EstimateCostFunc(tasks, zenrowsParams)
+}
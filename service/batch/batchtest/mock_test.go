@@ -0,0 +1,82 @@
+package batchtest_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/zenrows/zenrows-go-sdk/service/batch"
+	"github.com/zenrows/zenrows-go-sdk/service/batch/batchtest"
+)
+
+// submitAndWait submits a job and waits for its run, through any batch.IClient, as code under
+// test would.
+func submitAndWait(ctx context.Context, client batch.IClient, tasks []batch.Task) (batch.Run, error) {
+	submitted, err := client.SubmitJob(ctx, batch.SubmitJobRequest{Tasks: tasks})
+	if err != nil {
+		return batch.Run{}, err
+	}
+	return client.WaitForRun(ctx, submitted.JobID, batch.WaitForRunOptions{})
+}
+
+func TestMockClientAnswersWithTheFunctionsSet(t *testing.T) {
+	mock := &batchtest.MockClient{
+		SubmitJobFunc: func(_ context.Context, req batch.SubmitJobRequest) (*batch.SubmitJobResponse, error) {
+			return &batch.SubmitJobResponse{JobID: "job_123", AcceptedTasks: len(req.Tasks)}, nil
+		},
+		WaitForRunFunc: func(context.Context, string, batch.WaitForRunOptions) (batch.Run, error) {
+			return batch.Run{RunID: "run_1", Status: batch.RunStatusCompleted}, nil
+		},
+	}
+
+	run, err := submitAndWait(context.Background(), mock, []batch.Task{{URL: "https://example.com"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if run.Status != batch.RunStatusCompleted {
+		t.Fatalf("expected a completed run, got %+v", run)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 2 || calls[0].Name != "SubmitJob" || calls[1].Name != "WaitForRun" || calls[1].Args[0] != "job_123" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+}
+
+func TestMockClientWithoutAFunctionReturnsErrNotMocked(t *testing.T) {
+	mock := &batchtest.MockClient{}
+	if err := mock.DeleteRun(context.Background(), "job_123", "run_1"); !errors.Is(err, batchtest.ErrNotMocked) {
+		t.Fatalf("expected ErrNotMocked, got %v", err)
+	}
+	if calls := mock.Calls(); len(calls) != 1 || len(calls[0].Args) != 2 {
+		t.Fatalf("expected the call to be recorded with its arguments, got %+v", calls)
+	}
+}
+
+func TestMockClientIterMethods(t *testing.T) {
+	mock := &batchtest.MockClient{
+		IterResultsFunc: func(context.Context, string, batch.GetResultsOptions) iter.Seq2[batch.TaskResult, error] {
+			return func(yield func(batch.TaskResult, error) bool) {
+				_ = yield(batch.TaskResult{TaskID: "task_1"}, nil) && yield(batch.TaskResult{TaskID: "task_2"}, nil)
+			}
+		},
+	}
+
+	var taskIDs []string
+	for result, err := range mock.IterResults(context.Background(), "job_123", batch.GetResultsOptions{}) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		taskIDs = append(taskIDs, result.TaskID)
+	}
+	if len(taskIDs) != 2 {
+		t.Fatalf("expected the mocked results, got %v", taskIDs)
+	}
+
+	for _, err := range mock.IterJobs(context.Background(), batch.ListJobsOptions{}) {
+		if !errors.Is(err, batchtest.ErrNotMocked) {
+			t.Fatalf("expected ErrNotMocked, got %v", err)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -26,8 +27,10 @@ const apiKeyHeader = "X-API-Key" //nolint:gosec // header name, not a credential
 
 // Client is the Zenrows Batch API client.
 type Client struct {
-	cfg  options
-	http *resty.Client
+	cfg     options
+	http    *resty.Client
+	log     *slog.Logger
+	metrics Metrics
 }
 
 // NewClient creates and returns a new Zenrows Batch API client.
@@ -37,13 +40,35 @@ func NewClient(opts ...Option) *Client {
 		opt.apply(&client.cfg)
 	}
 
-	client.http = resty.New().
+	client.log = newLogger(client.cfg.logger, client.cfg.apiKey)
+	client.metrics = client.cfg.metrics
+	if client.metrics == nil {
+		client.metrics = noopMetrics{}
+	}
+	client.http = newRestyClient(client.cfg.httpClient).
 		SetBaseURL(client.cfg.baseURL).
 		SetHeader(apiKeyHeader, client.cfg.apiKey)
 
 	return client
 }
 
+// newRestyClient returns a resty client sending requests with a copy of httpClient, or with
+// a default client if it is nil.
+func newRestyClient(httpClient *http.Client) *resty.Client {
+	if httpClient == nil {
+		return resty.New()
+	}
+	hc := *httpClient
+	return resty.NewWithClient(&hc)
+}
+
+// storageClient returns the HTTP client for the presigned storage URLs the API hands out: the
+// one the API requests go through, so the transport set with WithHTTPClient (a proxy, a
+// recorder) applies to them too, but without the API key header.
+func (c *Client) storageClient() *http.Client {
+	return c.http.GetClient()
+}
+
 func (c *Client) isConfigured() bool {
 	return c.cfg.baseURL != "" && c.cfg.apiKey != ""
 }
@@ -57,7 +82,7 @@ func (c *Client) request(ctx context.Context, result any) *resty.Request {
 }
 
 func (c *Client) do(ctx context.Context, req *resty.Request, method, path string) error {
-	res, err := executeWithRetry(ctx, req, method, path, c.cfg.retries)
+	res, err := c.executeWithRetry(ctx, req, method, path)
 	if err != nil {
 		return err
 	}
@@ -463,7 +488,7 @@ func (c *Client) GetTaskContent(ctx context.Context, jobID, taskID string, opts
 	}
 
 	req := c.http.R().SetContext(ctx)
-	res, err := executeWithRetry(ctx, req, http.MethodGet, path, c.cfg.retries)
+	res, err := c.executeWithRetry(ctx, req, http.MethodGet, path)
 	if err != nil {
 		return nil, err
 	}
@@ -591,6 +616,7 @@ func (c *Client) WaitForRun(ctx context.Context, jobID string, opts WaitForRunOp
 
 	return pollUntil(ctx, fetch, isDone, isFailure, pollOptions{
 		Timeout: timeout, InitialInterval: pollInterval, MaxInterval: maxInterval,
+		Waiter: "run", Logger: c.log.With(slog.String("job_id", jobID)), Metrics: c.metrics,
 	})
 }
 
@@ -632,5 +658,6 @@ func (c *Client) WaitForIngest(ctx context.Context, jobID string, opts WaitForIn
 
 	return pollUntil(ctx, fetch, isDone, nil, pollOptions{
 		Timeout: timeout, InitialInterval: pollInterval, MaxInterval: maxInterval,
+		Waiter: "ingest", Logger: c.log.With(slog.String("job_id", jobID)), Metrics: c.metrics,
 	})
 }
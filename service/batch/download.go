@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -51,7 +52,7 @@ type DownloadedResult struct {
 // storage URL, so fetching it is one hop straight to the bucket (no API round-trip, no auth
 // header, and it keeps body bandwidth off the API).
 
-func fetchResultURL(ctx context.Context, task TaskResult) (body []byte, contentType string, err error) {
+func (c *Client) fetchResultURL(ctx context.Context, task TaskResult) (body []byte, contentType string, err error) {
 	if task.ResultURL == "" {
 		return nil, "", fmt.Errorf("task %q has no result_url — only successful tasks have a downloadable body", task.TaskID)
 	}
@@ -59,7 +60,7 @@ func fetchResultURL(ctx context.Context, task TaskResult) (body []byte, contentT
 	if err != nil {
 		return nil, "", err
 	}
-	res, err := (&http.Client{}).Do(req) //nolint:gosec // fetching a presigned URL returned by our own trusted API, not caller-controlled
+	res, err := c.storageClient().Do(req) //nolint:gosec // fetching a presigned URL returned by our own trusted API, not caller-controlled
 	if err != nil {
 		return nil, "", err
 	}
@@ -76,8 +77,8 @@ func fetchResultURL(ctx context.Context, task TaskResult) (body []byte, contentT
 
 // downloadTaskToFile downloads one task's body straight from its result_url and writes it to
 // targetPath.
-func downloadTaskToFile(task TaskResult, targetPath string) error {
-	body, _, err := fetchResultURL(context.Background(), task)
+func (c *Client) downloadTaskToFile(task TaskResult, targetPath string) error {
+	body, _, err := c.fetchResultURL(context.Background(), task)
 	if err != nil {
 		return err
 	}
@@ -89,12 +90,12 @@ func downloadTaskToFile(task TaskResult, targetPath string) error {
 
 // downloadTaskToMemory downloads one task's body straight from its result_url and returns
 // the raw bytes.
-func downloadTaskToMemory(task TaskResult) ([]byte, error) {
-	body, _, err := fetchResultURL(context.Background(), task)
+func (c *Client) downloadTaskToMemory(task TaskResult) ([]byte, error) {
+	body, _, err := c.fetchResultURL(context.Background(), task)
 	return body, err
 }
 
-func downloadExportToPath(ctx context.Context, export Export, targetPath string, chunkSize int) (string, error) {
+func (c *Client) downloadExportToPath(ctx context.Context, export Export, targetPath string, chunkSize int) (string, error) {
 	if export.Status != ExportStatusCompleted {
 		msg := export.Error
 		if msg == "" {
@@ -112,7 +113,7 @@ func downloadExportToPath(ctx context.Context, export Export, targetPath string,
 	if err != nil {
 		return "", err
 	}
-	res, err := (&http.Client{}).Do(req) //nolint:gosec // fetching a presigned URL returned by our own trusted API, not caller-controlled
+	res, err := c.storageClient().Do(req) //nolint:gosec // fetching a presigned URL returned by our own trusted API, not caller-controlled
 	if err != nil {
 		return "", err
 	}
@@ -233,10 +234,11 @@ func (c *Client) DownloadToDir(ctx context.Context, jobID, targetDir string, opt
 	allocator := newNameAllocator()
 
 	handle := func(row TaskResult) error {
-		body, _, err := fetchResultURL(ctx, row)
+		body, _, err := c.fetchResultURL(ctx, row)
 		if err != nil {
 			return err
 		}
+		c.metrics.AddCounter(MetricDownloadBytes, nil, float64(len(body)))
 		if len(body) > maxBytesPerFile {
 			return DownloadLimitExceededError{LimitName: "max_bytes_per_file", Limit: int64(maxBytesPerFile), Observed: int64(len(body))}
 		}
@@ -291,10 +293,11 @@ func (c *Client) DownloadToMemory(ctx context.Context, jobID string, opts Downlo
 	var totalBytes int64
 
 	handle := func(row TaskResult) error {
-		body, contentType, err := fetchResultURL(ctx, row)
+		body, contentType, err := c.fetchResultURL(ctx, row)
 		if err != nil {
 			return err
 		}
+		c.metrics.AddCounter(MetricDownloadBytes, nil, float64(len(body)))
 		if len(body) > maxBytesPerFile {
 			return DownloadLimitExceededError{LimitName: "max_bytes_per_file", Limit: int64(maxBytesPerFile), Observed: int64(len(body))}
 		}
@@ -341,6 +344,8 @@ func runBulk(
 				return written, err
 			}
 			written++
+			c.log.DebugContext(ctx, "batch: download progress", slog.String("job_id", jobID), slog.String("task_id", row.TaskID),
+				slog.Int("downloaded", written))
 		}
 		return written, nil
 	}
@@ -385,7 +390,10 @@ func runBulk(
 			}
 			mu.Lock()
 			written++
+			downloaded := written
 			mu.Unlock()
+			c.log.DebugContext(ctx, "batch: download progress", slog.String("job_id", jobID), slog.String("task_id", row.TaskID),
+				slog.Int("downloaded", downloaded))
 		}(row)
 	}
 	wg.Wait()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -95,6 +96,8 @@ func (c *Client) WaitForExport(ctx context.Context, jobID, runID, exportID strin
 
 	return pollUntil(ctx, fetch, isDone, nil, pollOptions{
 		Timeout: timeout, InitialInterval: pollInterval, MaxInterval: maxInterval,
+		Waiter: "export", Logger: c.log.With(slog.String("job_id", jobID), slog.String("export_id", exportID)),
+		Metrics: c.metrics,
 	})
 }
 
@@ -147,12 +150,11 @@ func (c *Client) DownloadAllResults(ctx context.Context, jobID, runID, targetPat
 	if err := os.MkdirAll(filepath.Dir(targetPath), dirPerm); err != nil {
 		return "", err
 	}
-	bare := &http.Client{}
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, final.DownloadURL, http.NoBody)
 	if err != nil {
 		return "", err
 	}
-	res, err := bare.Do(httpReq) //nolint:gosec // fetching a presigned URL returned by our own trusted API, not caller-controlled
+	res, err := c.storageClient().Do(httpReq) //nolint:gosec // fetching a presigned URL returned by our own trusted API, not caller-controlled
 	if err != nil {
 		return "", err
 	}
@@ -171,8 +173,50 @@ func (c *Client) DownloadAllResults(ctx context.Context, jobID, runID, targetPat
 	}
 	defer f.Close()
 	buf := make([]byte, chunkSize)
-	if _, err := io.CopyBuffer(f, res.Body, buf); err != nil {
+	progress := &progressWriter{
+		ctx: ctx, w: f, total: res.ContentLength, metrics: c.metrics,
+		log: c.log.With(slog.String("job_id", jobID), slog.String("run_id", runID), slog.String("path", targetPath)),
+	}
+	if _, err := io.CopyBuffer(progress, res.Body, buf); err != nil {
 		return "", err
 	}
+	progress.finish()
 	return targetPath, nil
 }
+
+// progressLogBytes is how many bytes a download writes between two progress logs.
+const progressLogBytes = 8 << 20
+
+// progressWriter logs the progress of a download at debug level, every progressLogBytes and
+// once it is done, and counts the bytes written in the metrics.
+type progressWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	log     *slog.Logger
+	metrics Metrics
+	total   int64 // -1 when unknown
+	written int64
+	logged  int64 // written when progress was last logged
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.metrics.AddCounter(MetricDownloadBytes, nil, float64(n))
+	if p.written-p.logged >= progressLogBytes {
+		p.logProgress()
+	}
+	return n, err
+}
+
+// finish logs the final progress of the download, unless it was just logged.
+func (p *progressWriter) finish() {
+	if p.written != p.logged || p.written == 0 {
+		p.logProgress()
+	}
+}
+
+func (p *progressWriter) logProgress() {
+	p.logged = p.written
+	p.log.DebugContext(p.ctx, "batch: download progress", slog.Int64("bytes", p.written), slog.Int64("total_bytes", p.total))
+}
@@ -76,7 +76,6 @@ func (c *Client) UploadCSV(ctx context.Context, source any, opts UploadCSVOption
 		contentType = ct
 	}
 
-	bare := &http.Client{}
 	httpReq, err := http.NewRequestWithContext(ctx, created.Upload.Method, created.Upload.URL, bytes.NewReader(data))
 	if err != nil {
 		return "", err
@@ -86,7 +85,7 @@ func (c *Client) UploadCSV(ctx context.Context, source any, opts UploadCSVOption
 	}
 	httpReq.Header.Set("Content-Type", contentType)
 
-	res, err := bare.Do(httpReq) //nolint:gosec // fetching a presigned URL returned by our own trusted API, not caller-controlled
+	res, err := c.storageClient().Do(httpReq) //nolint:gosec // fetching a presigned URL returned by our own trusted API, not caller-controlled
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,94 @@
+package batch
+
+import (
+	"context"
+	"iter"
+)
+
+// IClient is the interface of the methods of Client that return data rather than resource
+// handles, e.g. to inject a fake client in code under test: Client implements it, and
+// batchtest.MockClient is a ready-made fake.
+//
+// The methods returning resource handles are left out: Job, Run, GetJob, GetRun,
+// SubmitRegular, SubmitOpen, SubmitScheduled, StartResultsExport and GetResultsExport. A
+// handle is bound to the Client that made it, to call the API again, so a fake could not
+// build a working one. Code under test can use the methods returning the same data instead,
+// e.g. SubmitJob rather than SubmitRegular, or ListJobs rather than GetJob.
+type IClient interface {
+	// SubmitJob submits a new scraping job.
+	SubmitJob(ctx context.Context, jobReq SubmitJobRequest) (*SubmitJobResponse, error)
+	// ListJobs lists jobs for the authenticated caller, newest first.
+	ListJobs(ctx context.Context, opts ListJobsOptions) (*ListJobsResponse, error)
+	// IterJobs auto-paginates ListJobs, yielding (Job, error) pairs.
+	IterJobs(ctx context.Context, opts ListJobsOptions) iter.Seq2[Job, error]
+	// DeleteJob deletes a job and all its artifacts.
+	DeleteJob(ctx context.Context, jobID string) error
+	// AddTasks appends tasks to an open job's initial run.
+	AddTasks(ctx context.Context, jobID string, tasks []Task, lastBatch bool) (*AddTasksResponse, error)
+	// CloseJob signals that no more tasks are coming for an open job.
+	CloseJob(ctx context.Context, jobID string) (*Job, error)
+	// StopRun terminally stops the current run of a job.
+	StopRun(ctx context.Context, jobID string) (*Run, error)
+	// Rerun starts a new run of a job — a full replay (opts.Status == "") or a partial
+	// retry (opts.Status is a comma-separated status filter like "failed" or
+	// "failed,pending").
+	Rerun(ctx context.Context, jobID string, opts RerunOptions) (*RerunJobResponse, error)
+	// ListRuns lists runs of a job, newest first.
+	ListRuns(ctx context.Context, jobID string, opts ListRunsOptions) (*ListRunsResponse, error)
+	// IterRuns auto-paginates ListRuns for jobID, yielding (Run, error) pairs.
+	IterRuns(ctx context.Context, jobID string, opts ListRunsOptions) iter.Seq2[Run, error]
+	// DeleteRun deletes one run and its artifacts.
+	DeleteRun(ctx context.Context, jobID, runID string) error
+	// GetResults pages through per-task results.
+	GetResults(ctx context.Context, jobID string, opts GetResultsOptions) (*GetResultsResponse, error)
+	// IterResults auto-paginates GetResults, yielding (TaskResult, error) pairs.
+	IterResults(ctx context.Context, jobID string, opts GetResultsOptions) iter.Seq2[TaskResult, error]
+	// GetTaskContent fetches the scraped content for one task.
+	GetTaskContent(ctx context.Context, jobID, taskID string, opts GetTaskContentOptions) ([]byte, error)
+	// GetTaskHistory returns the attempt history for one task.
+	GetTaskHistory(ctx context.Context, jobID, taskID, runID string) (*TaskHistoryResponse, error)
+	// WaitForRun blocks until a run reaches one of opts.TargetStatuses, polling with
+	// jittered exponential backoff.
+	WaitForRun(ctx context.Context, jobID string, opts WaitForRunOptions) (Run, error)
+	// WaitForIngest blocks until the current run's async-carrier ingestion has finished
+	// writing task rows.
+	WaitForIngest(ctx context.Context, jobID string, opts WaitForIngestOptions) (Job, error)
+	// DownloadToDir streams every (matching) task's body into targetDir.
+	DownloadToDir(ctx context.Context, jobID, targetDir string, opts DownloadToDirOptions) (int, error)
+	// DownloadToMemory loads every (matching) task body into a slice and returns it.
+	DownloadToMemory(ctx context.Context, jobID string, opts DownloadToMemoryOptions) ([]DownloadedResult, error)
+	// WaitForExport blocks until an export reaches a terminal state (defaults to
+	// {completed, failed}).
+	WaitForExport(ctx context.Context, jobID, runID, exportID string, opts WaitForExportOptions) (Export, error)
+	// DownloadAllResults starts an export of a run, waits for it, and saves the zip to
+	// targetPath.
+	DownloadAllResults(ctx context.Context, jobID, runID, targetPath string, opts DownloadAllResultsOptions) (string, error)
+	// CreateJobInput allocates a CSV upload slot (POST /job_inputs).
+	CreateJobInput(ctx context.Context, req CreateJobInputRequest) (*CreateJobInputResponse, error)
+	// UploadCSV allocates a CSV slot and PUTs the body to the presigned URL the server
+	// returns.
+	UploadCSV(ctx context.Context, source any, opts UploadCSVOptions) (string, error)
+	// GetJobWebhook returns the job's current webhook config.
+	GetJobWebhook(ctx context.Context, jobID string) (*WebhookConfig, error)
+	// PutJobWebhook replaces the job's webhook config wholesale.
+	PutJobWebhook(ctx context.Context, jobID string, config WebhookConfig) (*WebhookConfig, error)
+	// DeleteJobWebhook clears the job's webhook config.
+	DeleteJobWebhook(ctx context.Context, jobID string) error
+	// TestWebhook dispatches a synthetic webhook.test event to a receiver URL and reports
+	// the outcome, without touching any job.
+	TestWebhook(ctx context.Context, req TestWebhookRequest) (*TestWebhookResponse, error)
+	// ListHMACKeys lists the org's current HMAC key slots, without their secrets.
+	ListHMACKeys(ctx context.Context) (*HMACKeyList, error)
+	// RotateHMACKey generates the initial key or stages a rotation candidate.
+	RotateHMACKey(ctx context.Context) (*HMACKeyCreated, error)
+	// FinalizeHMACKey promotes the pending rotation candidate to active.
+	FinalizeHMACKey(ctx context.Context) (*HMACKeyFinalized, error)
+	// CancelHMACRotation discards the pending rotation candidate.
+	CancelHMACRotation(ctx context.Context) error
+	// EstimateCost estimates the credit cost of a job before submitting it. Pure — no
+	// network call.
+	EstimateCost(tasks []Task, zenrowsParams map[string]any) CostEstimate
+}
+
+// Client must implement IClient.
+var _ IClient = (*Client)(nil)
@@ -0,0 +1,84 @@
+package batch_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zenrows/zenrows-go-sdk/service/batch"
+)
+
+func TestWithLoggerLogsRetriesAndRedactsTheAPIKey(t *testing.T) {
+	const apiKey = "s3cr3t-api-key"
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"job_id":"job_123"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := batch.NewClient(batch.WithBaseURL(server.URL), batch.WithAPIKey(apiKey), batch.WithLogger(logger))
+	if _, err := client.GetJob(context.Background(), "job_123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	logs := buf.String()
+	for _, event := range []string{"batch: request start", "batch: request end", "batch: retrying request"} {
+		if !strings.Contains(logs, event) {
+			t.Errorf("expected a %q event, got:\n%s", event, logs)
+		}
+	}
+	if strings.Contains(logs, apiKey) {
+		t.Fatalf("expected the API key to be redacted, got:\n%s", logs)
+	}
+}
+
+func TestDownloadAllResultsThrottlesProgressLogs(t *testing.T) {
+	const size = 20 << 20
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/results.zip":
+			w.Write(make([]byte, size))
+		case r.Method == http.MethodPost:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"export_id":"exp_1","status":"pending"}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"export_id":"exp_1","status":"completed","download_url":"` + server.URL + `/results.zip"}`))
+		}
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := batch.NewClient(batch.WithBaseURL(server.URL), batch.WithAPIKey("k"), batch.WithLogger(logger))
+	path := filepath.Join(t.TempDir(), "results.zip")
+	_, err := client.DownloadAllResults(context.Background(), "job_123", "run_1", path,
+		batch.DownloadAllResultsOptions{PollInterval: time.Millisecond, ChunkSize: 32 << 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// one record every 8 MiB, and one once done: 640 writes of 32 KiB make 3 records
+	if n := strings.Count(buf.String(), "batch: download progress"); n != 3 {
+		t.Fatalf("expected 3 progress records, got %d", n)
+	}
+	if !strings.Contains(buf.String(), `"bytes":20971520`) {
+		t.Fatalf("expected the final progress to be logged, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,73 @@
+package batch_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/zenrows/zenrows-go-sdk/service/batch"
+)
+
+func TestWithMetricsRecordsRequestsRetriesAndPolls(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit := hits.Add(1)
+		if hit == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		status := batch.RunStatusRunning
+		if hit >= 3 {
+			status = batch.RunStatusCompleted
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(batch.Job{JobID: "job_123", LatestRun: &batch.Run{RunID: "run_1", Status: status}})
+	}))
+	defer server.Close()
+
+	metrics := batch.NewMemoryMetrics()
+	client := batch.NewClient(batch.WithBaseURL(server.URL), batch.WithAPIKey("k"), batch.WithMetrics(metrics))
+	if _, err := client.WaitForRun(context.Background(), "job_123", batch.WaitForRunOptions{PollInterval: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	snapshot := metrics.Snapshot()
+	if got := snapshot.Counter(batch.MetricRequests, map[string]string{"method": "GET"}); got != 3 {
+		t.Errorf("expected 3 attempts, got %g", got)
+	}
+	if got := snapshot.Counter(batch.MetricRequests, map[string]string{"status": "503"}); got != 1 {
+		t.Errorf("expected 1 attempt with a 503, got %g", got)
+	}
+	if got := snapshot.Counter(batch.MetricRetries, nil); got != 1 {
+		t.Errorf("expected 1 retry, got %g", got)
+	}
+	if got := snapshot.Counter(batch.MetricPolls, map[string]string{"waiter": "run"}); got != 2 {
+		t.Errorf("expected 2 polls, got %g", got)
+	}
+	if count, _ := snapshot.Duration(batch.MetricRequestDuration, nil); count != 3 {
+		t.Errorf("expected 3 request durations, got %d", count)
+	}
+}
+
+func TestMemoryMetricsWritesPrometheusText(t *testing.T) {
+	metrics := batch.NewMemoryMetrics()
+	metrics.AddCounter(batch.MetricDownloadBytes, nil, 1024)
+	metrics.AddCounter(batch.MetricPolls, map[string]string{"waiter": "export"}, 3)
+
+	var b strings.Builder
+	if err := metrics.WritePrometheus(&b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# TYPE zenrows_batch_download_bytes_total counter\n" +
+		"zenrows_batch_download_bytes_total 1024\n" +
+		"# TYPE zenrows_batch_polls_total counter\n" +
+		"zenrows_batch_polls_total{waiter=\"export\"} 3\n"
+	if b.String() != want {
+		t.Errorf("unexpected exposition:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
@@ -1,6 +1,10 @@
 package batch
 
-import "os"
+import (
+	"log/slog"
+	"net/http"
+	"os"
+)
 
 const defaultBaseURL = "https://async.api.zenrows.com/v1"
 
@@ -18,6 +22,10 @@ type options struct {
 	baseURL string
 	apiKey  string
 	retries int
+	logger  *slog.Logger
+	metrics Metrics
+	// httpClient sends the requests; nil means a default client.
+	httpClient *http.Client
 }
 
 func defaultOptions() options {
@@ -56,3 +64,13 @@ func WithRetries(retries int) Option {
 		o.retries = retries
 	}}
 }
+
+// WithHTTPClient configures the HTTP client that sends the requests, e.g. to set a proxy, or
+// a transport that records and replays them in tests (see the recorder package of the Fetch
+// API SDK, github.com/zenrows/zenrows-go-sdk/service/api/recorder). It also sends the
+// requests to presigned storage URLs, e.g. to download results or upload a CSV file. The
+// client is copied, so it is not modified. Defaults to a client with a cookie jar and the
+// default transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return &funcOption{f: func(o *options) { o.httpClient = httpClient }}
+}
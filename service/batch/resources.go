@@ -219,13 +219,13 @@ func (r RunRef) DownloadToMemory(ctx context.Context, opts DownloadToMemoryOptio
 // DownloadTaskToFile downloads one task's body straight from its presigned result_url to
 // target.
 func (r RunRef) DownloadTaskToFile(task TaskResult, targetPath string) error {
-	return downloadTaskToFile(task, targetPath)
+	return r.client.downloadTaskToFile(task, targetPath)
 }
 
 // DownloadTaskToMemory downloads one task's body straight from its presigned result_url and
 // returns the raw bytes.
 func (r RunRef) DownloadTaskToMemory(task TaskResult) ([]byte, error) {
-	return downloadTaskToMemory(task)
+	return r.client.downloadTaskToMemory(task)
 }
 
 // Wait blocks until this run reaches a target state. Returns a fresh loaded RunHandle so
@@ -320,7 +320,7 @@ func (r ExportRef) DownloadToPath(ctx context.Context, targetPath string) (strin
 	if err != nil {
 		return "", err
 	}
-	return downloadExportToPath(ctx, loaded.Data, targetPath, defaultExportChunkSize)
+	return r.client.downloadExportToPath(ctx, loaded.Data, targetPath, defaultExportChunkSize)
 }
 
 // ExportHandle is an ExportRef plus a guaranteed, synchronous Data snapshot.
@@ -431,12 +431,12 @@ func (cr CurrentRun) DownloadToMemory(ctx context.Context, opts DownloadToMemory
 
 // DownloadTaskToFile downloads one task's body straight from its presigned result_url.
 func (cr CurrentRun) DownloadTaskToFile(task TaskResult, targetPath string) error {
-	return downloadTaskToFile(task, targetPath)
+	return cr.client.downloadTaskToFile(task, targetPath)
 }
 
 // DownloadTaskToMemory downloads one task's body straight from its presigned result_url.
 func (cr CurrentRun) DownloadTaskToMemory(task TaskResult) ([]byte, error) {
-	return downloadTaskToMemory(task)
+	return cr.client.downloadTaskToMemory(task)
 }
 
 // StartExport kicks off an async zip of the current run's bodies. Resolves the current run id
@@ -3,6 +3,7 @@ package batch
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"math/rand"
 	"net/http"
 	"strconv"
@@ -65,19 +66,31 @@ func retryAfterDuration(res *resty.Response) (time.Duration, bool) {
 // or a network error) up to maxRetries times with jittered exponential backoff (honoring
 // Retry-After when present). Only idempotent requests are replayed: GET/PUT/DELETE/HEAD/
 // OPTIONS, plus POST when the caller supplied an Idempotency-Key header. Context
-// cancellation/timeout is never retried — the caller set that budget.
-func executeWithRetry(ctx context.Context, req *resty.Request, method, path string, maxRetries int) (*resty.Response, error) {
+// cancellation/timeout is never retried — the caller set that budget. Every attempt, retry
+// and backoff wait is reported to the logger and metrics of the client.
+func (c *Client) executeWithRetry(ctx context.Context, req *resty.Request, method, path string) (*resty.Response, error) {
+	maxRetries, log := c.cfg.retries, c.log
+	methodLabels := map[string]string{"method": method}
 	idempotent := idempotentMethods[method] || (method == http.MethodPost && hasIdempotencyKey(req))
 
 	attempt := 0
 	for {
+		attrs := []any{slog.String("method", method), slog.String("path", path), slog.Int("attempt", attempt+1)}
+		log.DebugContext(ctx, "batch: request start", attrs...)
+		sentAt := time.Now()
 		res, err := req.Execute(method, path)
+		c.metrics.ObserveDuration(MetricRequestDuration, methodLabels, time.Since(sentAt))
 		if err != nil {
+			c.metrics.AddCounter(MetricRequests, map[string]string{"method": method, "status": "error"}, 1)
+			log.WarnContext(ctx, "batch: request failed", append(attrs, slog.Any("error", err))...)
 			if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
 				return res, err
 			}
 			if idempotent && attempt < maxRetries {
-				if !sleepCtx(ctx, backoffDuration(attempt)) {
+				wait := backoffDuration(attempt)
+				log.InfoContext(ctx, "batch: retrying request", append(attrs, slog.Duration("wait", wait), slog.Any("error", err))...)
+				c.metrics.AddCounter(MetricRetries, methodLabels, 1)
+				if !sleepCtx(ctx, wait) {
 					return res, ctx.Err()
 				}
 				attempt++
@@ -85,12 +98,18 @@ func executeWithRetry(ctx context.Context, req *resty.Request, method, path stri
 			}
 			return res, err
 		}
+		c.metrics.AddCounter(MetricRequests, map[string]string{"method": method, "status": strconv.Itoa(res.StatusCode())}, 1)
+		log.DebugContext(ctx, "batch: request end",
+			append(attrs, slog.Int("status", res.StatusCode()), slog.Duration("duration", res.Time()))...)
 
 		if idempotent && attempt < maxRetries && retryableStatuses[res.StatusCode()] {
 			wait, ok := retryAfterDuration(res)
 			if !ok {
 				wait = backoffDuration(attempt)
 			}
+			log.InfoContext(ctx, "batch: retrying request",
+				append(attrs, slog.Duration("wait", wait), slog.Int("status", res.StatusCode()))...)
+			c.metrics.AddCounter(MetricRetries, methodLabels, 1)
 			if !sleepCtx(ctx, wait) {
 				return res, ctx.Err()
 			}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -166,3 +167,41 @@ func TestWithRetriesZeroDisablesRetries(t *testing.T) {
 		t.Fatalf("expected exactly 1 attempt with WithRetries(0), got %d", got)
 	}
 }
+
+// roundTripperFunc adapts a function to an http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestPresignedRequestsGoThroughTheConfiguredTransport(t *testing.T) {
+	upload := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "" {
+			t.Errorf("expected no API key on the presigned request")
+		}
+	}))
+	defer upload.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"file_input_id":"fi_1","upload":{"method":"PUT","url":"` + upload.URL + `/slot?X-Amz-Signature=s"}}`))
+	}))
+	defer server.Close()
+
+	var hosts []string
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		hosts = append(hosts, req.URL.Host)
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	client := batch.NewClient(
+		batch.WithBaseURL(server.URL),
+		batch.WithAPIKey("k"),
+		batch.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if _, err := client.UploadCSV(context.Background(), []byte("url\nhttps://example.com\n"), batch.UploadCSVOptions{URLField: 0, Header: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hosts) != 2 || hosts[1] != strings.TrimPrefix(upload.URL, "http://") {
+		t.Fatalf("expected the presigned upload to go through the transport, got requests to %v", hosts)
+	}
+}
@@ -121,6 +121,15 @@ type Task struct {
 	ZenRowsParams map[string]any `json:"zenrows_params,omitempty"`
 }
 
+// TasksFromURLs returns one Task per URL, e.g. for the page URLs read from a sitemap with the Fetch API SDK's sitemap package.
+func TasksFromURLs(urls ...string) []Task {
+	tasks := make([]Task, 0, len(urls))
+	for _, u := range urls {
+		tasks = append(tasks, Task{URL: u})
+	}
+	return tasks
+}
+
 // WebhookConfig describes where (and how) to notify on run.completed / run.failed events.
 type WebhookConfig struct {
 	URL string `json:"url"`
@@ -0,0 +1,14 @@
+package batch_test
+
+import (
+	"testing"
+
+	"github.com/zenrows/zenrows-go-sdk/service/batch"
+)
+
+func TestTasksFromURLs(t *testing.T) {
+	tasks := batch.TasksFromURLs("https://a", "https://b")
+	if len(tasks) != 2 || tasks[0].URL != "https://a" || tasks[1].URL != "https://b" {
+		t.Fatalf("expected one task per url, got %+v", tasks)
+	}
+}
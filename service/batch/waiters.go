@@ -3,6 +3,7 @@ package batch
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"math/rand"
 	"time"
 )
@@ -32,6 +33,9 @@ type pollOptions struct {
 	MaxInterval     time.Duration
 	Backoff         float64
 	Jitter          float64
+	Waiter          string       // names the waiter in logs and metrics: run, ingest or export
+	Logger          *slog.Logger // receives an event per poll; nil disables logging
+	Metrics         Metrics      // counts the polls; nil disables metrics
 }
 
 func defaultPollOptions() pollOptions {
@@ -56,6 +60,12 @@ func withPollDefaults(opts pollOptions) pollOptions {
 	if opts.MaxInterval <= 0 {
 		opts.MaxInterval = defaults.MaxInterval
 	}
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.DiscardHandler)
+	}
+	if opts.Metrics == nil {
+		opts.Metrics = noopMetrics{}
+	}
 	if opts.Backoff <= 0 {
 		opts.Backoff = defaults.Backoff
 	}
@@ -81,8 +91,9 @@ func pollUntil[T any](
 	deadline := time.Now().Add(opts.Timeout)
 	interval := opts.InitialInterval
 
-	for {
+	for poll := 1; ; poll++ {
 		var zero T
+		opts.Metrics.AddCounter(MetricPolls, map[string]string{"waiter": opts.Waiter}, 1)
 		value, err := fetch(ctx)
 		if err != nil {
 			return zero, err
@@ -106,6 +117,8 @@ func pollUntil[T any](
 		}
 		jitterFactor := 1.0 + (rand.Float64()*2-1)*opts.Jitter //nolint:gosec // timing jitter, not security-sensitive
 		sleep = time.Duration(float64(sleep) * jitterFactor)
+		opts.Logger.DebugContext(ctx, "batch: waiter poll", slog.String("waiter", opts.Waiter), slog.Int("poll", poll),
+			slog.Any("value", value), slog.Duration("wait", sleep))
 		if sleep > 0 {
 			timer := time.NewTimer(sleep)
 			select {